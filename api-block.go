@@ -0,0 +1,177 @@
+package mefs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	files "github.com/ipfs/go-ipfs-files"
+)
+
+// defaultBlockConcurrency - blocks in flight of the batch operations
+// when no concurrency is given.
+const defaultBlockConcurrency = 4
+
+// BlockGetReader - returns the content of a block as a stream, the
+// caller must close it. With block verification on, the block is
+// buffered and a mismatch is returned by Read instead of io.EOF.
+func (c *Client) BlockGetReader(ctx context.Context, path string) (io.ReadCloser, error) {
+	resp, err := c.Request("block/get", path).Send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		resp.Close()
+		return nil, resp.Error
+	}
+	if c.verifyBlocks {
+		return &verifyingReader{ReadCloser: resp.Output, key: path}, nil
+	}
+	return resp.Output, nil
+}
+
+// BlockPutReader - stores the content of r as a block and returns
+// its key.
+func (c *Client) BlockPutReader(ctx context.Context, r io.Reader, format, mhtype string, mhlen int) (string, error) {
+	var out struct {
+		Key string
+	}
+
+	var buf *bytes.Buffer
+	if c.verifyBlocks {
+		buf = new(bytes.Buffer)
+		r = io.TeeReader(r, buf)
+	}
+
+	fr := files.NewReaderFile(r)
+	slf := files.NewSliceDirectory([]files.DirEntry{files.FileEntry("", fr)})
+	fileReader := files.NewMultiFileReader(slf, true)
+
+	err := c.Request("block/put").
+		Option("mhtype", mhtype).
+		Option("format", format).
+		Option("mhlen", mhlen).
+		Body(fileReader).
+		Exec(ctx, &out)
+	if err != nil {
+		return "", err
+	}
+	if buf != nil {
+		if err = VerifyBlock(out.Key, buf.Bytes()); err != nil {
+			return "", err
+		}
+	}
+	return out.Key, nil
+}
+
+// BlockResult is the outcome of a block operation on Key.
+type BlockResult struct {
+	Key string
+	Err error
+}
+
+// BlockRm - removes blocks from the local store. With force, blocks
+// which do not exist are not reported as failures. The results hold
+// the per-block errors, only a failed request is returned as error.
+func (c *Client) BlockRm(ctx context.Context, force bool, keys ...string) ([]BlockResult, error) {
+	stream, err := c.Request("block/rm", keys...).
+		Option("force", force).
+		Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var results []BlockResult
+	for {
+		var out struct {
+			Hash  string
+			Error string
+		}
+		err := stream.Next(&out)
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return results, err
+		}
+		res := BlockResult{Key: out.Hash}
+		if out.Error != "" {
+			res.Err = &Error{Command: "block/rm", Message: out.Error}
+		}
+		results = append(results, res)
+	}
+}
+
+// BlockPutOptions - block format and multihash of BlockPutMany.
+type BlockPutOptions struct {
+	Format string
+	MhType string
+	MhLen  int
+}
+
+// BlockPutMany - stores blocks with at most concurrency puts in
+// flight. Results are in the order of blocks.
+func (c *Client) BlockPutMany(ctx context.Context, blocks [][]byte, opts BlockPutOptions, concurrency int) []BlockResult {
+	results := make([]BlockResult, len(blocks))
+	runBlocks(ctx, len(blocks), concurrency, func(i int) {
+		results[i].Key, results[i].Err = c.BlockPutReader(ctx, bytes.NewReader(blocks[i]), opts.Format, opts.MhType, opts.MhLen)
+	}, func(i int, err error) {
+		results[i].Err = err
+	})
+	return results
+}
+
+// BlockData is a block fetched by BlockGetMany.
+type BlockData struct {
+	Key  string
+	Data []byte
+	Err  error
+}
+
+// BlockGetMany - fetches blocks with at most concurrency gets in
+// flight. Results are in the order of keys.
+func (c *Client) BlockGetMany(ctx context.Context, keys []string, concurrency int) []BlockData {
+	results := make([]BlockData, len(keys))
+	runBlocks(ctx, len(keys), concurrency, func(i int) {
+		results[i].Key = keys[i]
+		rc, err := c.BlockGetReader(ctx, keys[i])
+		if err != nil {
+			results[i].Err = err
+			return
+		}
+		defer rc.Close()
+		results[i].Data, results[i].Err = ioutil.ReadAll(rc)
+	}, func(i int, err error) {
+		results[i].Key = keys[i]
+		results[i].Err = err
+	})
+	return results
+}
+
+// runBlocks - runs do for the n blocks with bounded concurrency, the
+// blocks not started before ctx is done are passed to cancel.
+func runBlocks(ctx context.Context, n, concurrency int, do func(i int), cancel func(i int, err error)) {
+	if concurrency <= 0 {
+		concurrency = defaultBlockConcurrency
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			cancel(i, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			do(i)
+		}(i)
+	}
+	wg.Wait()
+}
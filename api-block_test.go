@@ -0,0 +1,102 @@
+package mefs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newBlockNode - a fake node storing blocks under "key-<data>".
+func newBlockNode(t *testing.T) *fakeNode {
+	var mutex sync.Mutex
+	store := make(map[string][]byte)
+	n := newFakeNode(t)
+	n.Handle("block/put", func(w http.ResponseWriter, r *http.Request) {
+		data := readUpload(t, r)
+		key := "key-" + string(data)
+		mutex.Lock()
+		store[key] = data
+		mutex.Unlock()
+		fmt.Fprintf(w, `{"Key":"%s","Size":%d}`, key, len(data))
+	})
+	n.Handle("block/get", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		data, ok := store[r.URL.Query().Get("arg")]
+		mutex.Unlock()
+		if !ok {
+			nodeError(w, "block not found")
+			return
+		}
+		w.Write(data)
+	})
+	n.Handle("block/rm", func(w http.ResponseWriter, r *http.Request) {
+		for _, key := range r.URL.Query()["arg"] {
+			mutex.Lock()
+			_, ok := store[key]
+			delete(store, key)
+			mutex.Unlock()
+			if !ok && r.URL.Query().Get("force") != "true" {
+				fmt.Fprintf(w, `{"Hash":"%s","Error":"block not found"}`+"\n", key)
+				continue
+			}
+			fmt.Fprintf(w, `{"Hash":"%s"}`+"\n", key)
+		}
+	})
+	return n
+}
+
+func TestBlockBatch(t *testing.T) {
+	n := newBlockNode(t)
+	defer n.Close()
+	c := n.Client()
+
+	key, err := c.BlockPutReader(context.Background(), strings.NewReader("single"), "v0", "sha2-256", -1)
+	if err != nil || key != "key-single" {
+		t.Fatalf("unexpected put %q, %v", key, err)
+	}
+	rc, err := c.BlockGetReader(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(rc)
+	rc.Close()
+	if string(data) != "single" {
+		t.Errorf("unexpected block %q", data)
+	}
+
+	blocks := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	puts := c.BlockPutMany(context.Background(), blocks, BlockPutOptions{Format: "v0", MhType: "sha2-256", MhLen: -1}, 2)
+	keys := []string{"key-missing"}
+	for i, res := range puts {
+		if res.Err != nil || res.Key != "key-"+string(blocks[i]) {
+			t.Errorf("unexpected put result %+v", res)
+		}
+		keys = append(keys, res.Key)
+	}
+
+	gets := c.BlockGetMany(context.Background(), keys, 2)
+	if gets[0].Err == nil {
+		t.Error("expected error for missing block")
+	}
+	for i, res := range gets[1:] {
+		if res.Err != nil || !bytes.Equal(res.Data, blocks[i]) {
+			t.Errorf("unexpected get result %+v", res)
+		}
+	}
+
+	results, err := c.BlockRm(context.Background(), false, "key-a", "key-missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("unexpected rm results %+v", results)
+	}
+	if results, err = c.BlockRm(context.Background(), true, "key-missing"); err != nil || results[0].Err != nil {
+		t.Errorf("unexpected forced rm results %+v, %v", results, err)
+	}
+}
@@ -0,0 +1,203 @@
+package mefs
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChallengeResult is the outcome of a proof-of-storage challenge of
+// a provider.
+type ChallengeResult struct {
+	Provider string
+
+	// Key is the challenged block, or block prefix for a range of
+	// blocks; Bucket and Object are set by AuditBucket.
+	Key    string
+	Bucket string
+	Object string
+
+	Success bool
+	Latency time.Duration
+
+	// Message is the reply of the node, Err the reason of a failure.
+	Message string
+	Err     error
+}
+
+// ChallengeTest - challenges provider to on the blocks of key. A
+// challenge refused by the node is reported as a failed result; an
+// invalid request, an unknown command or a transport error is
+// returned.
+func (c Client) ChallengeTest(key, to string, options ...LfsOpts) (*ChallengeResult, error) {
+	return c.challenge(context.Background(), key, to, options...)
+}
+
+func (c Client) challenge(ctx context.Context, key, to string, options ...LfsOpts) (*ChallengeResult, error) {
+	var res string
+	rb := c.Request("dht/challengeTest", key, to)
+	for _, option := range options {
+		option(rb)
+	}
+
+	start := time.Now()
+	err := rb.Exec(ctx, &res)
+	result := &ChallengeResult{
+		Provider: to,
+		Key:      key,
+		Latency:  time.Since(start),
+		Message:  res,
+	}
+	if err != nil {
+		if !challengeRejected(err) {
+			return nil, err
+		}
+		result.Err = err
+		return result, nil
+	}
+	result.Success = true
+	return result, nil
+}
+
+// challengeRejected - returns true when err is the refusal of a
+// challenge by the node rather than a failure to run it, that is a
+// command error sent by the node itself as a JSON 500 response.
+func challengeRejected(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.decoded && e.StatusCode == http.StatusInternalServerError && e.Code == cmdsErrNormal
+}
+
+// AuditOptions - options of AuditBucket.
+type AuditOptions struct {
+	// Prefix restricts the audit to the objects with this prefix.
+	Prefix string
+
+	// Concurrency is the number of challenges in flight, defaults
+	// to 4.
+	Concurrency int
+
+	// Rate limits the challenges per second, zero means no limit.
+	Rate float64
+
+	// Options are passed to each challenge.
+	Options []LfsOpts
+}
+
+// AuditReport is the result of AuditBucket.
+type AuditReport struct {
+	Bucket     string
+	Objects    int
+	Challenges int
+	Duration   time.Duration
+
+	// Failed holds the failed challenges, FailingProviders and
+	// FailingObjects count them by provider and by object.
+	Failed           []ChallengeResult
+	FailingProviders map[string]int
+	FailingObjects   map[string]int
+}
+
+// Healthy - returns true when every challenge succeeded.
+func (r AuditReport) Healthy() bool {
+	return len(r.Failed) == 0
+}
+
+// AuditBucket - challenges every provider on every block of the
+// objects of bucketName, with bounded concurrency and rate.
+func (c Client) AuditBucket(ctx context.Context, bucketName string, opts AuditOptions) (*AuditReport, error) {
+	start := time.Now()
+	objs, err := c.listObjectsQuery(ctx, bucketName, opts.Prefix, "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	type job struct {
+		object string
+		block  BlockLocation
+	}
+	var jobs []job
+	for _, obj := range objs.Contents {
+		blocks, err := c.ListObjectBlocks(ctx, bucketName, obj.Key)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range blocks {
+			jobs = append(jobs, job{object: obj.Key, block: b})
+		}
+	}
+
+	report := &AuditReport{
+		Bucket:           bucketName,
+		Objects:          len(objs.Contents),
+		FailingProviders: make(map[string]int),
+		FailingObjects:   make(map[string]int),
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	var tick <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var (
+		mutex    sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, j := range jobs {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := c.challenge(ctx, j.block.BlockID, j.block.Provider, opts.Options...)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			report.Challenges++
+			if !res.Success {
+				res.Bucket = bucketName
+				res.Object = j.object
+				report.Failed = append(report.Failed, *res)
+				report.FailingProviders[res.Provider]++
+				report.FailingObjects[j.object]++
+			}
+		}(j)
+	}
+	wg.Wait()
+
+	sort.Slice(report.Failed, func(i, k int) bool {
+		if report.Failed[i].Object != report.Failed[k].Object {
+			return report.Failed[i].Object < report.Failed[k].Object
+		}
+		return report.Failed[i].Key < report.Failed[k].Key
+	})
+	report.Duration = time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, firstErr
+}
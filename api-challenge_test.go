@@ -0,0 +1,79 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAuditBucket(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("lfs/list_objects", `{"Objects":[{"ObjectName":"o1"},{"ObjectName":"o2"}]}`)
+	n.Handle("lfs/list_blocks", func(w http.ResponseWriter, r *http.Request) {
+		object := r.URL.Query()["arg"][1]
+		fmt.Fprintf(w, `{"Blocks":[{"BlockID":"%s_0_0","Provider":"p1"},{"BlockID":"%s_0_1","Provider":"p2"}]}`, object, object)
+	})
+	challenge := func(w http.ResponseWriter, r *http.Request) {
+		args := r.URL.Query()["arg"]
+		if args[1] == "p2" && args[0] == "o2_0_1" {
+			nodeError(w, "proof mismatch")
+			return
+		}
+		fmt.Fprint(w, `"ok"`)
+	}
+	n.Handle("dht/challengeTest", challenge)
+
+	c := n.Client()
+
+	res, err := c.ChallengeTest("o1_0_0", "p1")
+	if err != nil || !res.Success || res.Message != "ok" {
+		t.Fatalf("unexpected challenge result %+v, %v", res, err)
+	}
+
+	n.Handle("dht/challengeTest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"Message":"invalid provider","Code":1,"Type":"error"}`)
+	})
+	if _, err = c.ChallengeTest("o1_0_0", "bad"); err == nil {
+		t.Error("expected an invalid argument error")
+	}
+	// a proxy error page is not a refusal of the node.
+	n.Handle("dht/challengeTest", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "<html><body>502 Bad Gateway</body></html>")
+	})
+	if _, err = c.ChallengeTest("o1_0_0", "p1"); err == nil {
+		t.Error("expected a bad gateway error")
+	}
+	n.Handle("dht/challengeTest", challenge)
+	n2 := newFakeNode(t)
+	defer n2.Close()
+	if _, err = n2.Client().ChallengeTest("o1_0_0", "p1"); err == nil {
+		t.Error("expected a command not found error")
+	}
+
+	// the listing is cancelled with ctx.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.AuditBucket(ctx, "bucket01", AuditOptions{}); err == nil || n.Calls("lfs/list_objects") != 0 {
+		t.Errorf("expected a cancelled audit, got %v", err)
+	}
+
+	report, err := c.AuditBucket(context.Background(), "bucket01", AuditOptions{Concurrency: 2, Rate: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Objects != 2 || report.Challenges != 4 {
+		t.Errorf("expected 4 challenges on 2 objects, got %d on %d", report.Challenges, report.Objects)
+	}
+	if report.Healthy() || len(report.Failed) != 1 || report.FailingProviders["p2"] != 1 || report.FailingObjects["o2"] != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if f := report.Failed[0]; f.Key != "o2_0_1" || f.Err == nil {
+		t.Errorf("unexpected failure %+v", f)
+	}
+}
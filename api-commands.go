@@ -0,0 +1,296 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Command is a node command with its options, arguments and
+// subcommands. Arguments are empty when the node does not describe
+// them.
+type Command struct {
+	Name        string
+	Subcommands []Command
+	Options     []CommandOption
+	Arguments   []CommandArgument
+}
+
+// CommandOption is an option of a command, Names holds the name and
+// its aliases.
+type CommandOption struct {
+	Names       []string
+	Type        string
+	Description string
+}
+
+// CommandArgument is an argument of a command.
+type CommandArgument struct {
+	Name        string
+	Type        string
+	Required    bool
+	Variadic    bool
+	Description string
+}
+
+// Find - returns the subcommand at path, e.g. "lfs/list_objects",
+// nil when there is none.
+func (cmd *Command) Find(path string) *Command {
+	cur := cmd
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		var next *Command
+		for i := range cur.Subcommands {
+			if cur.Subcommands[i].Name == name {
+				next = &cur.Subcommands[i]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
+
+// HasOption - returns true when name is an option of the command or
+// one of its aliases.
+func (cmd *Command) HasOption(name string) bool {
+	for _, opt := range cmd.Options {
+		for _, n := range opt.Names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Paths - returns the paths of all the subcommands, in tree order.
+func (cmd *Command) Paths() []string {
+	var paths []string
+	var walk func(prefix string, c *Command)
+	walk = func(prefix string, c *Command) {
+		for i := range c.Subcommands {
+			path := prefix + c.Subcommands[i].Name
+			paths = append(paths, path)
+			walk(path+"/", &c.Subcommands[i])
+		}
+	}
+	walk("", cmd)
+	return paths
+}
+
+// Commands - returns the command tree of the node, the root command
+// holds the global options.
+func (c *Client) Commands(ctx context.Context) (*Command, error) {
+	var root Command
+	if err := c.Request("commands").Option("flags", true).Exec(ctx, &root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// UnknownCommandError is returned in strict mode for a command, an
+// option or a number of arguments the node does not accept.
+type UnknownCommandError struct {
+	Command string
+	Option  string // empty unless the option is unknown
+	Version string // version of the node
+	Message string
+}
+
+func (e *UnknownCommandError) Error() string {
+	return fmt.Sprintf("mefs: %s (node version %s)", e.Message, e.Version)
+}
+
+// optionsAlwaysAccepted - options set on every request by the client.
+var optionsAlwaysAccepted = map[string]bool{
+	"encoding":        true,
+	"stream-channels": true,
+}
+
+// commandsNotValidated - commands used by the strict mode itself.
+var commandsNotValidated = map[string]bool{
+	"commands": true,
+	"version":  true,
+}
+
+// commandCache holds the command trees of the node by version, it is
+// shared by the clients derived from the same client.
+type commandCache struct {
+	mu      sync.Mutex
+	version string
+	trees   map[string]*Command
+
+	// fetch is the lookup of the node version and tree in flight,
+	// shared by the concurrent callers.
+	fetch *treeFetch
+}
+
+// treeFetch is a lookup of the node version and command tree, done
+// is closed once the result is set.
+type treeFetch struct {
+	done    chan struct{}
+	version string
+	root    *Command
+	err     error
+}
+
+// SetStrictCommands - turns the strict mode on or off. In strict mode
+// the command, options and arguments of every request are checked
+// against the command tree of the node before being sent, unknown
+// ones fail with an *UnknownCommandError. The tree is fetched once
+// per node version.
+func (c *Client) SetStrictCommands(enabled bool) {
+	if !enabled {
+		c.commands = nil
+		return
+	}
+	if c.commands == nil {
+		c.commands = &commandCache{trees: make(map[string]*Command)}
+	}
+}
+
+// tree - returns the command tree of the node version, refreshing
+// the version first when refresh is set. The node is queried without
+// holding the lock, concurrent callers share the same lookup.
+func (cc *commandCache) tree(ctx context.Context, c *Client, refresh bool) (string, *Command, error) {
+	cc.mu.Lock()
+	if root, ok := cc.trees[cc.version]; ok && !refresh {
+		version := cc.version
+		cc.mu.Unlock()
+		return version, root, nil
+	}
+	f := cc.fetch
+	if f == nil {
+		f = &treeFetch{done: make(chan struct{})}
+		cc.fetch = f
+		cc.mu.Unlock()
+
+		f.version, f.root, f.err = cc.lookup(ctx, c)
+		cc.mu.Lock()
+		cc.fetch = nil
+		if f.err == nil {
+			cc.version = f.version
+			cc.trees[f.version] = f.root
+		}
+		cc.mu.Unlock()
+		close(f.done)
+	} else {
+		cc.mu.Unlock()
+	}
+
+	select {
+	case <-f.done:
+		return f.version, f.root, f.err
+	case <-ctx.Done():
+		return "", nil, ctx.Err()
+	}
+}
+
+// lookup - queries the node version and its command tree, unless the
+// tree of that version is cached.
+func (cc *commandCache) lookup(ctx context.Context, c *Client) (string, *Command, error) {
+	version, _, err := c.versionContext(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	cc.mu.Lock()
+	root, ok := cc.trees[version]
+	cc.mu.Unlock()
+	if ok {
+		return version, root, nil
+	}
+	if root, err = c.Commands(ctx); err != nil {
+		return "", nil, err
+	}
+	return version, root, nil
+}
+
+// validateCommand - the interceptor of the strict mode. A rejected
+// request is checked again against the current node version, in case
+// the node was upgraded.
+func (c *Client) validateCommand(ctx context.Context, req *Request, next Handler) (*Response, error) {
+	cc := c.commands
+	if cc == nil || commandsNotValidated[req.Command] {
+		return next(ctx, req)
+	}
+
+	version, root, err := cc.tree(ctx, c, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkCommand(root, version, req); err != nil {
+		prev := version
+		if version, root, err = cc.tree(ctx, c, true); err != nil {
+			return nil, err
+		}
+		if version == prev {
+			return nil, checkCommand(root, version, req)
+		}
+		if err = checkCommand(root, version, req); err != nil {
+			return nil, err
+		}
+	}
+	return next(ctx, req)
+}
+
+// checkCommand - checks the command, options and arguments of req
+// against the tree of the node.
+func checkCommand(root *Command, version string, req *Request) error {
+	cmd := root.Find(req.Command)
+	if cmd == nil {
+		return &UnknownCommandError{Command: req.Command, Version: version,
+			Message: "unknown command " + req.Command}
+	}
+
+	// options of the parent commands apply to their subcommands.
+	scopes := []*Command{root}
+	parts := strings.Split(strings.Trim(req.Command, "/"), "/")
+	for i := 1; i <= len(parts); i++ {
+		scopes = append(scopes, root.Find(strings.Join(parts[:i], "/")))
+	}
+	for name := range req.Opts {
+		if optionsAlwaysAccepted[name] {
+			continue
+		}
+		known := false
+		for _, scope := range scopes {
+			if scope.HasOption(name) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return &UnknownCommandError{Command: req.Command, Option: name, Version: version,
+				Message: "unknown option " + name + " of command " + req.Command}
+		}
+	}
+
+	if len(cmd.Arguments) == 0 {
+		return nil
+	}
+	required, variadic := 0, false
+	for _, arg := range cmd.Arguments {
+		if arg.Required {
+			required++
+		}
+		variadic = variadic || arg.Variadic
+	}
+	// a body holds the argument of file commands.
+	n := len(req.Args)
+	if req.Body != nil {
+		n++
+	}
+	if n < required || (!variadic && n > len(cmd.Arguments)) {
+		want := fmt.Sprintf("%d to %d", required, len(cmd.Arguments))
+		if variadic {
+			want = fmt.Sprintf("at least %d", required)
+		}
+		return &UnknownCommandError{Command: req.Command, Version: version,
+			Message: fmt.Sprintf("command %s takes %s arguments, got %d", req.Command, want, n)}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testCommandTree = `{"Name":"mefs","Options":[{"Names":["timeout"]}],"Subcommands":[
+	{"Name":"version","Subcommands":[],"Options":[]},
+	{"Name":"lfs","Options":[{"Names":["address"]}],"Subcommands":[
+		{"Name":"start","Options":[{"Names":["password","pwd"]},{"Names":["secretkey","sk"]}],
+			"Arguments":[{"Name":"address","Type":"string","Required":true}]},
+		{"Name":"head_bucket","Options":[],"Arguments":[{"Name":"bucket","Required":true}]}
+	]}
+]}`
+
+func TestCommands(t *testing.T) {
+	version := "0.4.22"
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("commands", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("flags") != "true" {
+			t.Error("expected the flags option")
+		}
+		if version == "0.4.23" {
+			fmt.Fprint(w, `{"Name":"mefs","Subcommands":[{"Name":"lfs","Subcommands":[{"Name":"head_Bucket"}]}]}`)
+			return
+		}
+		fmt.Fprint(w, testCommandTree)
+	})
+	n.Handle("version", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Version":%q}`, version)
+	})
+	n.Reply("lfs/start", `{}`)
+	n.Reply("lfs/head_Bucket", `{}`)
+
+	c := n.Client()
+	ctx := context.Background()
+
+	root, err := c.Commands(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := root.Find("lfs/start")
+	if start == nil || !start.HasOption("pwd") || len(start.Arguments) != 1 || !start.Arguments[0].Required {
+		t.Errorf("unexpected lfs/start %+v", start)
+	}
+	if paths := root.Paths(); len(paths) != 4 || paths[3] != "lfs/head_bucket" {
+		t.Errorf("unexpected paths %v", paths)
+	}
+
+	// not strict, typos reach the node.
+	if err := c.StartUser("0x01", SetSecretKey("sk")); err != nil {
+		t.Error(err)
+	}
+
+	c.SetStrictCommands(true)
+	if err := c.StartUser("0x01", SetPassword("pwd")); err != nil {
+		t.Error(err)
+	}
+	err = c.StartUser("0x01", SetSecretKey("sk"))
+	if uc, ok := err.(*UnknownCommandError); !ok || uc.Option != "secretekey" || uc.Version != "0.4.22" {
+		t.Errorf("expected an unknown option error, got %v", err)
+	}
+	err = c.Request("lfs/start").Exec(ctx, nil)
+	if _, ok := err.(*UnknownCommandError); !ok {
+		t.Errorf("expected a missing argument error, got %v", err)
+	}
+	err = c.Request("lfs/head_Bucket", "b0").Option("timeout", "1s").Exec(ctx, nil)
+	if uc, ok := err.(*UnknownCommandError); !ok || uc.Command != "lfs/head_Bucket" {
+		t.Errorf("expected an unknown command error, got %v", err)
+	}
+	if calls := n.Calls("lfs/start"); calls != 2 {
+		t.Errorf("expected 2 lfs/start sent, got %d", calls)
+	}
+	if calls := n.Calls("commands"); calls != 2 {
+		t.Errorf("expected the tree fetched once in strict mode, got %d", calls-1)
+	}
+
+	// after an upgrade, the tree of the new version is fetched.
+	version = "0.4.23"
+	if err := c.Request("lfs/head_Bucket", "b0").Exec(ctx, nil); err != nil {
+		t.Errorf("expected the command of the new version to pass, got %v", err)
+	}
+	if calls := n.Calls("commands"); calls != 3 {
+		t.Errorf("expected the tree fetched again, got %d", calls-1)
+	}
+}
+
+func TestCommandsConcurrent(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("commands", testCommandTree)
+	n.Handle("version", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, `{"Version":"0.4.22"}`)
+	})
+	n.Reply("lfs/head_bucket", `{}`)
+
+	c := n.Client()
+	c.SetStrictCommands(true)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Request("lfs/head_bucket", "b0").Exec(context.Background(), nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, cmds := n.Calls("version"), n.Calls("commands"); v != 1 || cmds != 1 {
+		t.Errorf("expected one shared lookup, got %d version and %d commands requests", v, cmds)
+	}
+}
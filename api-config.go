@@ -0,0 +1,81 @@
+package mefs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	files "github.com/ipfs/go-ipfs-files"
+)
+
+// configEntry is the reply of the config command.
+type configEntry struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// ConfigGet - reads the value of the config key, e.g. "Bootstrap" or
+// "Addresses.API", and decodes it into v, a pointer to an
+// interface{} or to a caller-provided struct.
+func (c *Client) ConfigGet(ctx context.Context, key string, v interface{}) error {
+	if key == "" {
+		return ErrInvalidArgument("Config key cannot be empty.")
+	}
+	var out configEntry
+	if err := c.Request("config", key).Exec(ctx, &out); err != nil {
+		return err
+	}
+	return json.Unmarshal(out.Value, v)
+}
+
+// ConfigSet - sets the config key to value. Strings are stored as is,
+// any other value is encoded as JSON.
+func (c *Client) ConfigSet(ctx context.Context, key string, value interface{}) error {
+	if key == "" {
+		return ErrInvalidArgument("Config key cannot be empty.")
+	}
+
+	rb := c.Request("config")
+	if s, ok := value.(string); ok {
+		rb.Arguments(key, s)
+	} else {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		rb.Arguments(key, string(b)).Option("json", true)
+	}
+	return rb.Exec(ctx, nil)
+}
+
+// ConfigShow - decodes the whole node config into v, a pointer to an
+// interface{} or to a caller-provided struct.
+func (c *Client) ConfigShow(ctx context.Context, v interface{}) error {
+	return c.Request("config/show").Exec(ctx, v)
+}
+
+// ConfigReplace - replaces the whole node config with cfg, encoded as
+// JSON. A json.RawMessage or []byte cfg is sent unchanged.
+func (c *Client) ConfigReplace(ctx context.Context, cfg interface{}) error {
+	var b []byte
+	switch v := cfg.(type) {
+	case json.RawMessage:
+		b = v
+	case []byte:
+		b = v
+	default:
+		var err error
+		if b, err = json.Marshal(cfg); err != nil {
+			return err
+		}
+	}
+	if !json.Valid(b) {
+		return ErrInvalidArgument("Config is not valid JSON.")
+	}
+
+	fr := files.NewReaderFile(bytes.NewReader(b))
+	slf := files.NewSliceDirectory([]files.DirEntry{files.FileEntry("", fr)})
+	fileReader := files.NewMultiFileReader(slf, true)
+
+	return c.Request("config/replace").Body(fileReader).Exec(ctx, nil)
+}
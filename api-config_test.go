@@ -0,0 +1,70 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	var setArgs []string
+	var setJSON string
+	var replaced map[string]interface{}
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("config", func(w http.ResponseWriter, r *http.Request) {
+		args := r.URL.Query()["arg"]
+		if len(args) == 2 {
+			setArgs = args
+			setJSON = r.URL.Query().Get("json")
+			fmt.Fprintf(w, `{"Key":%q,"Value":%s}`, args[0], args[1])
+			return
+		}
+		fmt.Fprint(w, `{"Key":"Datastore","Value":{"StorageMax":"10GB","GCPeriod":"1h"}}`)
+	})
+	n.Reply("config/show", `{"Addresses":{"API":"/ip4/127.0.0.1/tcp/5001"}}`)
+	n.Handle("config/replace", func(w http.ResponseWriter, r *http.Request) {
+		json.Unmarshal(readUpload(t, r), &replaced)
+	})
+
+	c := n.Client()
+	ctx := context.Background()
+
+	var ds struct {
+		StorageMax string
+		GCPeriod   string
+	}
+	if err := c.ConfigGet(ctx, "Datastore", &ds); err != nil || ds.StorageMax != "10GB" {
+		t.Errorf("unexpected config value %+v, %v", ds, err)
+	}
+	var any interface{}
+	if err := c.ConfigGet(ctx, "Datastore", &any); err != nil {
+		t.Error(err)
+	} else if m, ok := any.(map[string]interface{}); !ok || m["GCPeriod"] != "1h" {
+		t.Errorf("unexpected config value %v", any)
+	}
+
+	if err := c.ConfigSet(ctx, "Bootstrap", []string{"/ip4/1.2.3.4/tcp/4001"}); err != nil {
+		t.Error(err)
+	}
+	if len(setArgs) != 2 || setArgs[1] != `["/ip4/1.2.3.4/tcp/4001"]` || setJSON != "true" {
+		t.Errorf("unexpected config set %v, json=%q", setArgs, setJSON)
+	}
+	if err := c.ConfigSet(ctx, "", "x"); err == nil {
+		t.Error("expected an error for an empty key")
+	}
+
+	var cfg map[string]interface{}
+	if err := c.ConfigShow(ctx, &cfg); err != nil || cfg["Addresses"] == nil {
+		t.Errorf("unexpected config %v, %v", cfg, err)
+	}
+
+	if err := c.ConfigReplace(ctx, cfg); err != nil {
+		t.Error(err)
+	}
+	if replaced["Addresses"] == nil {
+		t.Errorf("unexpected replaced config %v", replaced)
+	}
+}
@@ -0,0 +1,105 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"time"
+)
+
+// Statuses of a user contract.
+const (
+	ContractPending = "pending"
+	ContractActive  = "active"
+	ContractExpired = "expired"
+)
+
+// UserContract is the storage contract of a user: the period it is
+// paid for, the space and price, and the peers storing the user data.
+type UserContract struct {
+	Address   string
+	Status    string
+	Start     time.Time
+	Duration  time.Duration
+	Capacity  uint64
+	Price     *big.Int
+	Keepers   []string
+	Providers []string
+}
+
+// End - returns the end of the contract period.
+func (uc UserContract) End() time.Time {
+	return uc.Start.Add(uc.Duration)
+}
+
+// UnmarshalJSON decodes the price, sent as a JSON number or string,
+// and the start time and duration, sent in seconds.
+func (uc *UserContract) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Address   string
+		Status    string
+		Start     int64
+		Duration  int64
+		Capacity  uint64
+		Price     json.RawMessage
+		Keepers   []string
+		Providers []string
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	price, err := decodeAmount(raw.Price)
+	if err != nil {
+		return err
+	}
+
+	*uc = UserContract{
+		Address:   raw.Address,
+		Status:    raw.Status,
+		Duration:  time.Duration(raw.Duration) * time.Second,
+		Capacity:  raw.Capacity,
+		Price:     price,
+		Keepers:   raw.Keepers,
+		Providers: raw.Providers,
+	}
+	if raw.Start > 0 {
+		uc.Start = time.Unix(raw.Start, 0)
+	}
+	return nil
+}
+
+// GetUserContract - returns the storage contract of the user address.
+func (c *Client) GetUserContract(ctx context.Context, address string) (*UserContract, error) {
+	if !isValidAddress(address) {
+		return nil, ErrInvalidArgument("Invalid user address " + address + ".")
+	}
+
+	var out UserContract
+	if err := c.Request("contract/user", address).Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBalance - returns the balance of the address, in its smallest
+// unit.
+func (c *Client) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	if !isValidAddress(address) {
+		return nil, ErrInvalidArgument("Invalid address " + address + ".")
+	}
+
+	var out struct {
+		Balance json.RawMessage
+	}
+	if err := c.Request("contract/balance", address).Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	balance, err := decodeAmount(out.Balance)
+	if err != nil {
+		return nil, err
+	}
+	if balance == nil {
+		balance = new(big.Int)
+	}
+	return balance, nil
+}
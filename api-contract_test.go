@@ -0,0 +1,50 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestUserContract(t *testing.T) {
+	const user = "0x0123456789abcdef0123456789abcdef01234567"
+	n := newFakeNode(t)
+	defer n.Close()
+	checkArgs := func(r *http.Request) {
+		if args := r.URL.Query()["arg"]; len(args) != 1 || args[0] != user {
+			t.Errorf("unexpected args %v", args)
+		}
+	}
+	n.Handle("contract/user", func(w http.ResponseWriter, r *http.Request) {
+		checkArgs(r)
+		fmt.Fprintf(w, `{"Address":%q,"Status":"active","Start":1588320000,"Duration":2592000,"Capacity":1073741824,"Price":"1000000000","Keepers":[%q],"Providers":[%q]}`,
+			user, testPeerID, testPeerID2)
+	})
+	n.Handle("contract/balance", func(w http.ResponseWriter, r *http.Request) {
+		checkArgs(r)
+		fmt.Fprintf(w, `{"Address":%q,"Balance":"99000000000000000000"}`, user)
+	})
+
+	c := n.Client()
+	ctx := context.Background()
+
+	uc, err := c.GetUserContract(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uc.Status != ContractActive || uc.Duration != 30*24*time.Hour || uc.Price.Int64() != 1e9 ||
+		len(uc.Keepers) != 1 || uc.Providers[0] != testPeerID2 || uc.End().Unix() != 1588320000+2592000 {
+		t.Errorf("unexpected contract %+v", uc)
+	}
+
+	balance, err := c.GetBalance(ctx, user)
+	if err != nil || balance.String() != "99000000000000000000" {
+		t.Errorf("unexpected balance %v, %v", balance, err)
+	}
+
+	if _, err := c.GetBalance(ctx, "0x1234"); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
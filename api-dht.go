@@ -0,0 +1,134 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+// ErrRoutingNotFound is the Extra of the QueryError ending a query
+// stream which found nothing.
+var ErrRoutingNotFound = errors.New("routing: not found")
+
+func (t QueryEventType) String() string {
+	switch t {
+	case SendingQuery:
+		return "SendingQuery"
+	case PeerResponse:
+		return "PeerResponse"
+	case FinalPeer:
+		return "FinalPeer"
+	case QueryError:
+		return "QueryError"
+	case Provider:
+		return "Provider"
+	case Value:
+		return "Value"
+	case AddingPeer:
+		return "AddingPeer"
+	case DialingPeer:
+		return "DialingPeer"
+	}
+	return "Unknown"
+}
+
+// UnmarshalJSON decodes a query event, the node leaves ID empty for
+// events which are not about a peer.
+func (qe *QueryEvent) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		ID        json.RawMessage
+		Type      QueryEventType
+		Responses []*peer.AddrInfo
+		Extra     string
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*qe = QueryEvent{Type: raw.Type, Responses: raw.Responses, Extra: raw.Extra}
+	if len(raw.ID) > 0 && string(raw.ID) != `""` && string(raw.ID) != "null" {
+		if err := json.Unmarshal(raw.ID, &qe.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFromStream - streams the events of the query of key on peerID.
+// The stream ends with either a Value event or a QueryError event
+// without ID, a QueryError with the ID of a peer reports that this
+// peer failed and the query goes on.
+func (c Client) GetFromStream(ctx context.Context, key, peerID string, options ...LfsOpts) (<-chan QueryEvent, error) {
+	rb := c.Request("dht/getfrom", key, peerID)
+	for _, option := range options {
+		option(rb)
+	}
+	return queryEvents(ctx, rb, Value)
+}
+
+// FindPeerStream - streams the events of the lookup of peerID. The
+// stream ends with either a FinalPeer event or a QueryError event
+// without ID, as for GetFromStream.
+func (c Client) FindPeerStream(ctx context.Context, peerID string) (<-chan QueryEvent, error) {
+	return queryEvents(ctx, c.Request("dht/findpeer", peerID), FinalPeer)
+}
+
+// queryEvents - sends rb and streams its query events until the final
+// event, errors are sent as a QueryError event.
+func queryEvents(ctx context.Context, rb *RequestBuilder, final QueryEventType) (<-chan QueryEvent, error) {
+	stream, err := rb.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan QueryEvent)
+	send := func(ev QueryEvent) bool {
+		select {
+		case out <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	stream.pump(func() interface{} { return new(QueryEvent) }, func(v interface{}, err error) bool {
+		if err != nil {
+			send(QueryEvent{Type: QueryError, Extra: err.Error()})
+			return false
+		}
+		ev := *v.(*QueryEvent)
+		return send(ev) && ev.Type != final && !queryFailed(ev)
+	}, func(eof bool) {
+		if eof {
+			send(QueryEvent{Type: QueryError, Extra: ErrRoutingNotFound.Error()})
+		}
+		close(out)
+	})
+	return out, nil
+}
+
+// queryFailed - reports whether ev ends its query with an error, the
+// errors of single peers carry their ID and do not end the query.
+func queryFailed(ev QueryEvent) bool {
+	return ev.Type == QueryError && ev.ID == ""
+}
+
+// lastQueryEvent - drains events and returns the final one, skipping
+// the errors of single peers.
+func lastQueryEvent(events <-chan QueryEvent) (*QueryEvent, error) {
+	var last *QueryEvent
+	for ev := range events {
+		if ev.Type == QueryError && !queryFailed(ev) {
+			continue
+		}
+		ev := ev
+		last = &ev
+	}
+	if last == nil {
+		return nil, ErrRoutingNotFound
+	}
+	if last.Type == QueryError {
+		return nil, errors.New(last.Extra)
+	}
+	return last, nil
+}
@@ -0,0 +1,86 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+const testPeerID = "QmYyQSo1c1Ym7orWxLYvCrM2EmxFTANf8wXmmE7DWjhx5N"
+
+func TestFindPeerStream(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("dht/findpeer", fmt.Sprintf(`{"ID":"%s","Type":0,"Responses":null,"Extra":""}`+"\n"+
+		`{"ID":"","Type":2,"Responses":[{"ID":"%s","Addrs":["/ip4/1.2.3.4/tcp/4001"]}],"Extra":""}`+"\n", testPeerID, testPeerID))
+	n.Reply("dht/getfrom", fmt.Sprintf(`{"ID":"%s","Type":0,"Responses":null,"Extra":""}`+"\n", testPeerID))
+
+	c := n.Client()
+
+	events, err := c.FindPeerStream(context.Background(), testPeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []QueryEventType
+	for ev := range events {
+		types = append(types, ev.Type)
+	}
+	if len(types) != 2 || types[0] != SendingQuery || types[1] != FinalPeer {
+		t.Fatalf("unexpected events %v", types)
+	}
+
+	info, err := c.FindPeer(testPeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ID != testPeerID || len(info.Addrs) != 1 || info.Addrs[0] != "/ip4/1.2.3.4/tcp/4001" {
+		t.Errorf("unexpected peer info %+v", info)
+	}
+
+	// A query ending without value ends with a QueryError event.
+	events, err = c.GetFromStream(context.Background(), "key", testPeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last QueryEvent
+	for ev := range events {
+		last = ev
+	}
+	if last.Type != QueryError || last.Extra != ErrRoutingNotFound.Error() {
+		t.Errorf("expected QueryError, got %v %q", last.Type, last.Extra)
+	}
+	if _, err = c.GetFrom("key", testPeerID); err == nil {
+		t.Error("expected error from GetFrom")
+	}
+}
+
+func TestFindPeerStreamPeerError(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("dht/findpeer", fmt.Sprintf(`{"ID":"%s","Type":0,"Responses":null,"Extra":""}`+"\n"+
+		`{"ID":"%s","Type":3,"Responses":null,"Extra":"failed to dial"}`+"\n"+
+		`{"ID":"","Type":2,"Responses":[{"ID":"%s","Addrs":["/ip4/1.2.3.4/tcp/4001"]}],"Extra":""}`+"\n", testPeerID, testPeerID, testPeerID))
+
+	c := n.Client()
+
+	// The error of a single peer is forwarded and the lookup goes on.
+	events, err := c.FindPeerStream(context.Background(), testPeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []QueryEventType
+	for ev := range events {
+		types = append(types, ev.Type)
+	}
+	if len(types) != 3 || types[0] != SendingQuery || types[1] != QueryError || types[2] != FinalPeer {
+		t.Fatalf("unexpected events %v", types)
+	}
+
+	info, err := c.FindPeer(testPeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ID != testPeerID || len(info.Addrs) != 1 {
+		t.Errorf("unexpected peer info %+v", info)
+	}
+}
@@ -0,0 +1,225 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthStatus is the status of a health check or of a whole node.
+type HealthStatus string
+
+// Health statuses, a check is skipped when it does not apply, e.g.
+// the LFS checks of a client without an address.
+const (
+	HealthOK       HealthStatus = "ok"
+	HealthDegraded HealthStatus = "degraded"
+	HealthFailed   HealthStatus = "failed"
+	HealthSkipped  HealthStatus = "skipped"
+)
+
+// Names of the checks run by Diagnose.
+const (
+	CheckVersion = "version"
+	CheckID      = "id"
+	CheckSwarm   = "swarm"
+	CheckKeepers = "keepers"
+	CheckLfs     = "lfs"
+	CheckStorage = "storage"
+)
+
+// HealthCheck is the outcome of one check of Diagnose.
+type HealthCheck struct {
+	Name     string
+	Status   HealthStatus
+	Duration time.Duration
+	Error    string `json:",omitempty"`
+}
+
+// NodeHealth is the diagnostics report of a node. The fields of a
+// failed or skipped check keep their zero value.
+type NodeHealth struct {
+	Status   HealthStatus
+	Time     time.Time
+	Duration time.Duration
+
+	Version string
+	Commit  string
+
+	PeerID       string
+	AgentVersion string
+	Addresses    []string
+
+	SwarmPeers int
+
+	Keepers          int
+	KeepersConnected int
+
+	LfsStatus string
+	Storage   *RepoStat
+
+	Checks []HealthCheck
+}
+
+// Healthy - returns true when no check failed.
+func (nh NodeHealth) Healthy() bool {
+	return nh.Status == HealthOK
+}
+
+// Check - returns the check with name, nil when it was not run.
+func (nh NodeHealth) Check(name string) *HealthCheck {
+	for i := range nh.Checks {
+		if nh.Checks[i].Name == name {
+			return &nh.Checks[i]
+		}
+	}
+	return nil
+}
+
+// Diagnose - runs the health checks of the node concurrently and
+// gathers them in one report. The node is failed when it does not
+// answer the version check and degraded when any other check fails,
+// e.g. when none of the keepers of the user is connected.
+func (c *Client) Diagnose(ctx context.Context) *NodeHealth {
+	nh := &NodeHealth{Time: time.Now()}
+
+	address, _ := c.address(ctx)
+
+	checks := []struct {
+		name string
+		run  func() (HealthStatus, error)
+	}{
+		{CheckVersion, func() (HealthStatus, error) {
+			var ver struct {
+				Version string
+				Commit  string
+			}
+			if err := c.Request("version").Exec(ctx, &ver); err != nil {
+				return HealthFailed, err
+			}
+			nh.Version, nh.Commit = ver.Version, ver.Commit
+			return HealthOK, nil
+		}},
+		{CheckID, func() (HealthStatus, error) {
+			var id IdOutput
+			if err := c.Request("id").Exec(ctx, &id); err != nil {
+				return HealthFailed, err
+			}
+			nh.PeerID, nh.AgentVersion, nh.Addresses = id.ID, id.AgentVersion, id.Addresses
+			return HealthOK, nil
+		}},
+		{CheckSwarm, func() (HealthStatus, error) {
+			infos, err := c.SwarmPeers(ctx)
+			if err != nil {
+				return HealthFailed, err
+			}
+			nh.SwarmPeers = len(infos.Peers)
+			if nh.SwarmPeers == 0 {
+				return HealthDegraded, fmt.Errorf("no swarm peers")
+			}
+			return HealthOK, nil
+		}},
+		{CheckKeepers, func() (HealthStatus, error) {
+			if address == "" {
+				return HealthSkipped, nil
+			}
+			keepers, err := c.ListKeepersWithContext(ctx)
+			if err != nil {
+				return HealthFailed, err
+			}
+			if keepers != nil {
+				nh.Keepers = len(keepers.Peers)
+				for _, ps := range keepers.Peers {
+					if ps.Connected {
+						nh.KeepersConnected++
+					}
+				}
+			}
+			if nh.KeepersConnected == 0 {
+				return HealthDegraded, fmt.Errorf("none of %d keepers connected", nh.Keepers)
+			}
+			return HealthOK, nil
+		}},
+		{CheckLfs, func() (HealthStatus, error) {
+			if address == "" {
+				return HealthSkipped, nil
+			}
+			rb, err := c.lfsRequest(ctx, "lfs/show_storage")
+			if err != nil {
+				return HealthFailed, err
+			}
+			if err := rb.Exec(ctx, &nh.LfsStatus); err != nil {
+				return HealthFailed, err
+			}
+			return HealthOK, nil
+		}},
+		{CheckStorage, func() (HealthStatus, error) {
+			stat, err := c.RepoStat(ctx)
+			if err != nil {
+				return HealthFailed, err
+			}
+			nh.Storage = stat
+			if stat.StorageMax > 0 && stat.RepoSize >= stat.StorageMax {
+				return HealthDegraded, fmt.Errorf("repo size %d reached storage max %d", stat.RepoSize, stat.StorageMax)
+			}
+			return HealthOK, nil
+		}},
+	}
+
+	// each check fills its own fields of nh and its own entry of
+	// nh.Checks.
+	nh.Checks = make([]HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		nh.Checks[i].Name = check.name
+		wg.Add(1)
+		go func(hc *HealthCheck, run func() (HealthStatus, error)) {
+			defer wg.Done()
+			start := time.Now()
+			status, err := run()
+			hc.Status = status
+			hc.Duration = time.Since(start)
+			if err != nil {
+				hc.Error = err.Error()
+			}
+		}(&nh.Checks[i], check.run)
+	}
+	wg.Wait()
+
+	nh.Status = HealthOK
+	for _, hc := range nh.Checks {
+		switch {
+		case hc.Name == CheckVersion && hc.Status == HealthFailed:
+			nh.Status = HealthFailed
+		case nh.Status == HealthOK && (hc.Status == HealthFailed || hc.Status == HealthDegraded):
+			nh.Status = HealthDegraded
+		}
+	}
+	nh.Duration = time.Since(nh.Time)
+	return nh
+}
+
+// HealthHandler - returns an http.Handler serving the Diagnose report
+// as JSON, e.g. for readiness probes. The reply is 503 Service
+// Unavailable when the node failed and 200 OK otherwise, a timeout
+// bounds the checks when not zero.
+func (c *Client) HealthHandler(timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		nh := c.Diagnose(ctx)
+		w.Header().Set("Content-Type", "application/json")
+		if nh.Status == HealthFailed {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(nh)
+	})
+}
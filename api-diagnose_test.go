@@ -0,0 +1,65 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("version", `{"Version":"0.4.22","Commit":"abc123"}`)
+	n.Reply("id", fmt.Sprintf(`{"ID":%q,"Addresses":["/ip4/127.0.0.1/tcp/4001"],"AgentVersion":"mefs/user/0.4.22"}`, testPeerID))
+	n.Reply("swarm/peers", fmt.Sprintf(`{"Peers":[{"Addr":"/ip4/1.2.3.4/tcp/4001","Peer":%q}]}`, testPeerID2))
+	n.Handle("lfs/list_keepers", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") == "" {
+			t.Error("expected the address option")
+		}
+		fmt.Fprintf(w, `{"Peers":[{"PeerID":%q,"Connected":false},{"PeerID":%q,"Connected":false}]}`, testPeerID, testPeerID2)
+	})
+	n.Reply("lfs/show_storage", `"used 1.2 GB"`)
+	n.Reply("repo/stat", `{"NumObjects":3,"RepoSize":4096,"StorageMax":10000}`)
+
+	c, err := New(n.Listener.Addr().String(), "0x0123456789abcdef0123456789abcdef01234567", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nh := c.Diagnose(context.Background())
+	if nh.Status != HealthDegraded || nh.Healthy() {
+		t.Errorf("expected a degraded node, got %s", nh.Status)
+	}
+	if nh.Version != "0.4.22" || nh.AgentVersion != "mefs/user/0.4.22" || nh.SwarmPeers != 1 ||
+		nh.Keepers != 2 || nh.KeepersConnected != 0 || nh.LfsStatus != "used 1.2 GB" || nh.Storage.RepoSize != 4096 {
+		t.Errorf("unexpected report %+v", nh)
+	}
+	if hc := nh.Check(CheckKeepers); hc == nil || hc.Status != HealthDegraded || hc.Error == "" {
+		t.Errorf("unexpected keepers check %+v", hc)
+	}
+	if hc := nh.Check(CheckVersion); hc == nil || hc.Status != HealthOK {
+		t.Errorf("unexpected version check %+v", hc)
+	}
+
+	rec := httptest.NewRecorder()
+	c.HealthHandler(0).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	var served NodeHealth
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || served.Status != HealthDegraded || len(served.Checks) != 6 {
+		t.Errorf("unexpected reply %d %+v", rec.Code, served)
+	}
+
+	n.Handle("version", func(w http.ResponseWriter, r *http.Request) {
+		nodeError(w, "node stopped")
+	})
+	rec = httptest.NewRecorder()
+	c.HealthHandler(0).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for a stopped node, got %d", rec.Code)
+	}
+}
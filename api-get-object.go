@@ -607,12 +607,10 @@ func (c Client) getObject(ctx context.Context, bucketName, objectName string, op
 	if err != nil {
 		return nil, ObjectInfo{}, nil, err
 	}
-	rb := c.Request("lfs/get_object", bucketName, objectName)
-	creds, err := c.credsProvider.Get()
+	rb, err := c.lfsRequest(ctx, "lfs/get_object", bucketName, objectName)
 	if err != nil {
 		return nil, ObjectInfo{}, nil, err
 	}
-	rb.Option("address", creds.AccessKeyID)
 	resp, err := rb.Send(ctx)
 	if err != nil {
 		return nil, ObjectInfo{}, nil, err
@@ -0,0 +1,148 @@
+package mefs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxHealthChecks - number of peers checked concurrently.
+const maxHealthChecks = 8
+
+// PeerHealth is the health report of a storage peer.
+type PeerHealth struct {
+	PeerID string
+	Role   string
+
+	// Connected is the state reported by the keeper or provider list.
+	Connected bool
+
+	// Latency of the swarm connection, zero when not connected or
+	// not measured yet.
+	Latency time.Duration
+
+	// Reachable is true when the peer was found in the DHT, Addrs
+	// are the addresses it was found at.
+	Reachable bool
+	Addrs     []string
+
+	// AgentVersion of the peer, empty when it could not be identified.
+	AgentVersion string
+
+	// Errors of the failed checks, indexed by check name.
+	Errors map[string]string
+}
+
+// Degraded - returns true when the peer is not connected, not
+// reachable or one of its checks failed.
+func (ph PeerHealth) Degraded() bool {
+	return !ph.Connected || !ph.Reachable || len(ph.Errors) > 0
+}
+
+// fail - records the error of a failed check.
+func (ph *PeerHealth) fail(check string, err error) {
+	if ph.Errors == nil {
+		ph.Errors = make(map[string]string)
+	}
+	ph.Errors[check] = err.Error()
+}
+
+// StoragePeersHealth - returns the health of the keepers and the
+// providers of the user, options are passed to ListKeepers and
+// ListProviders.
+func (c Client) StoragePeersHealth(ctx context.Context, options ...LfsOpts) ([]PeerHealth, error) {
+	keepers, err := c.ListKeepersWithContext(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	providers, err := c.ListProvidersWithContext(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []PeerHealth
+	if keepers != nil {
+		for _, ps := range keepers.Peers {
+			reports = append(reports, PeerHealth{PeerID: ps.PeerID, Role: RoleKeeper, Connected: ps.Connected})
+		}
+	}
+	if providers != nil {
+		for _, ps := range providers.Peers {
+			reports = append(reports, PeerHealth{PeerID: ps.PeerID, Role: RoleProvider, Connected: ps.Connected})
+		}
+	}
+
+	latencies := make(map[string]time.Duration)
+	infos, swarmErr := c.SwarmPeers(ctx)
+	if swarmErr == nil {
+		for _, info := range infos.Peers {
+			if d, ok := info.LatencyDuration(); ok {
+				latencies[info.Peer] = d
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxHealthChecks)
+	for i := range reports {
+		reports[i].Latency = latencies[reports[i].PeerID]
+		if swarmErr != nil {
+			reports[i].fail("swarm", swarmErr)
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ph *PeerHealth) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.checkPeer(ctx, ph)
+		}(&reports[i])
+	}
+	wg.Wait()
+	return reports, ctx.Err()
+}
+
+// PeerHealthOf - returns the health of a single peer, Connected is
+// taken from the swarm connections of the node.
+func (c Client) PeerHealthOf(ctx context.Context, peerID string) PeerHealth {
+	ph := PeerHealth{PeerID: peerID}
+	infos, err := c.SwarmPeers(ctx)
+	if err != nil {
+		ph.fail("swarm", err)
+	} else {
+		for _, info := range infos.Peers {
+			if info.Peer == peerID {
+				ph.Connected = true
+				ph.Latency, _ = info.LatencyDuration()
+			}
+		}
+	}
+	c.checkPeer(ctx, &ph)
+	return ph
+}
+
+// checkPeer - fills the reachability and identity of ph.
+func (c Client) checkPeer(ctx context.Context, ph *PeerHealth) {
+	events, err := c.FindPeerStream(ctx, ph.PeerID)
+	if err == nil {
+		var final *QueryEvent
+		final, err = lastQueryEvent(events)
+		if err == nil {
+			ph.Reachable = true
+			for _, info := range final.Responses {
+				for _, addr := range info.Addrs {
+					ph.Addrs = append(ph.Addrs, addr.String())
+				}
+			}
+		}
+	}
+	if err != nil {
+		ph.fail("findpeer", err)
+	}
+
+	var id IdOutput
+	if err = c.Request("id", ph.PeerID).Exec(ctx, &id); err != nil {
+		ph.fail("id", err)
+		return
+	}
+	ph.AgentVersion = id.AgentVersion
+}
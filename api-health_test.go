@@ -0,0 +1,79 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const testPeerID2 = "QmcgpsyWgH8Y8ajJz1Cu72KnS5uo2Aa2LpzU7kinSupNKC"
+
+func TestStoragePeersHealth(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("lfs/list_keepers", fmt.Sprintf(`{"Peers":[{"PeerID":"%s","Connected":true}]}`, testPeerID))
+	n.Reply("lfs/list_providers", fmt.Sprintf(`{"Peers":[{"PeerID":"%s","Connected":false}]}`, testPeerID2))
+	n.Reply("swarm/peers", fmt.Sprintf(`{"Peers":[{"Addr":"/ip4/1.2.3.4/tcp/4001","Peer":"%s","Latency":"2ms"}]}`, testPeerID))
+	n.Handle("dht/findpeer", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("arg") != testPeerID {
+			return
+		}
+		fmt.Fprintf(w, `{"Type":2,"Responses":[{"ID":"%s","Addrs":["/ip4/1.2.3.4/tcp/4001"]}]}`, testPeerID)
+	})
+	n.Handle("id", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("arg") != testPeerID {
+			nodeError(w, "peer unreachable")
+			return
+		}
+		fmt.Fprint(w, `{"AgentVersion":"mefs/keeper/1.0"}`)
+	})
+
+	c := n.Client()
+	reports, err := c.StoragePeersHealth(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+
+	keeper, provider := reports[0], reports[1]
+	if keeper.Role != RoleKeeper || keeper.Degraded() || keeper.Latency != 2*time.Millisecond || keeper.AgentVersion != "mefs/keeper/1.0" {
+		t.Errorf("unexpected keeper health %+v", keeper)
+	}
+	if provider.Role != RoleProvider || !provider.Degraded() || provider.Reachable || provider.Errors["id"] == "" {
+		t.Errorf("unexpected provider health %+v", provider)
+	}
+
+	// a failed swarm listing is a failed check of every peer.
+	n.Handle("swarm/peers", func(w http.ResponseWriter, r *http.Request) {
+		nodeError(w, "swarm unavailable")
+	})
+	reports, err = c.StoragePeersHealth(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ph := range reports {
+		if !ph.Degraded() || ph.Errors["swarm"] == "" {
+			t.Errorf("expected a failed swarm check, got %+v", ph)
+		}
+	}
+}
+
+func TestPeerHealthOfPeerError(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("swarm/peers", fmt.Sprintf(`{"Peers":[{"Addr":"/ip4/1.2.3.4/tcp/4001","Peer":"%s","Latency":"2ms"}]}`, testPeerID))
+	// the lookup goes on after a peer on the way failed.
+	n.Reply("dht/findpeer", fmt.Sprintf(`{"ID":"%s","Type":0}`+"\n"+
+		`{"ID":"%s","Type":3,"Extra":"failed to dial"}`+"\n"+
+		`{"Type":2,"Responses":[{"ID":"%s","Addrs":["/ip4/1.2.3.4/tcp/4001"]}]}`+"\n", testPeerID2, testPeerID2, testPeerID))
+	n.Reply("id", `{"AgentVersion":"mefs/keeper/1.0"}`)
+
+	ph := n.Client().PeerHealthOf(context.Background(), testPeerID)
+	if !ph.Reachable || ph.Degraded() || len(ph.Addrs) != 1 || ph.Addrs[0] != "/ip4/1.2.3.4/tcp/4001" {
+		t.Errorf("unexpected peer health %+v", ph)
+	}
+}
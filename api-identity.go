@@ -0,0 +1,82 @@
+package mefs
+
+import (
+	"context"
+
+	"github.com/memoio/mefs-sdk-go/pkg/credentials"
+)
+
+// Identity is the MEFS user an lfs command is sent for. The password
+// unlocks the user on the node, it is sent by StartUser only.
+type Identity struct {
+	Address  string
+	Password string
+}
+
+// identityKey is the context key of the identity.
+type identityKey struct{}
+
+// ContextWithIdentity - returns a copy of ctx carrying the identity,
+// which overrides the client identity for the calls taking ctx.
+func ContextWithIdentity(ctx context.Context, address, password string) context.Context {
+	return context.WithValue(ctx, identityKey{}, Identity{Address: address, Password: password})
+}
+
+// IdentityFromContext - returns the identity carried by ctx.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// WithIdentity - returns a client acting as the user address. It
+// shares the transport, settings and interceptors of c and is cheap
+// to create, e.g. per request of a gateway serving many users.
+func (c *Client) WithIdentity(address, password string) *Client {
+	ic := *c
+	n := len(c.interceptors)
+	ic.interceptors = c.interceptors[:n:n]
+	ic.identity = &Identity{Address: address, Password: password}
+	ic.credsProvider = credentials.NewStaticV4(address, password, "")
+	return &ic
+}
+
+// identityOf - returns the identity carried by ctx, else the client
+// identity.
+func (c Client) identityOf(ctx context.Context) (Identity, bool) {
+	if id, ok := IdentityFromContext(ctx); ok {
+		return id, true
+	}
+	if c.identity != nil {
+		return *c.identity, true
+	}
+	return Identity{}, false
+}
+
+// address - returns the user address of the lfs commands: the one
+// carried by ctx, else the client identity, else the access key of
+// the client credentials.
+func (c Client) address(ctx context.Context) (string, error) {
+	if id, ok := c.identityOf(ctx); ok {
+		return id.Address, nil
+	}
+	creds, err := c.credsProvider.Get()
+	if err != nil {
+		return "", err
+	}
+	return creds.AccessKeyID, nil
+}
+
+// lfsRequest - returns a builder for an lfs command of the user
+// address, see address. Without an address the node picks its
+// default user.
+func (c Client) lfsRequest(ctx context.Context, command string, args ...string) (*RequestBuilder, error) {
+	address, err := c.address(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rb := c.Request(command, args...)
+	if address != "" {
+		rb.Option("address", address)
+	}
+	return rb, nil
+}
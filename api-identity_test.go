@@ -0,0 +1,115 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestWithIdentity(t *testing.T) {
+	const (
+		alice = "0x0123456789abcdef0123456789abcdef01234567"
+		bob   = "0x89abcdef0123456789abcdef0123456789abcdef"
+	)
+	var mu sync.Mutex
+	addrs := make(map[string]string)
+	n := newFakeNode(t)
+	defer n.Close()
+	reply := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			addrs[r.URL.Path] = r.URL.Query().Get("address")
+			mu.Unlock()
+			fmt.Fprint(w, body)
+		}
+	}
+	n.Handle("lfs/list_buckets", reply(`{"Buckets":[]}`))
+	n.Handle("lfs/list_keepers", reply(`{"Peers":[]}`))
+	n.Handle("lfs/head_Bucket", reply(`{"Buckets":[{"BucketName":"bucket0"}]}`))
+	n.Handle("lfs/list_objects", reply(`{"Objects":[{"ObjectName":"o0"}]}`))
+	var password string
+	n.Handle("lfs/start", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		addrs[r.URL.Path] = r.URL.Query().Get("arg")
+		password = r.URL.Query().Get("password")
+		mu.Unlock()
+		fmt.Fprint(w, `{}`)
+	})
+
+	c := n.Client()
+
+	if _, err := c.ListBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if addrs["/api/v0/lfs/list_buckets"] != "" {
+		t.Errorf("expected no address, got %q", addrs["/api/v0/lfs/list_buckets"])
+	}
+
+	ac := c.WithIdentity(alice, "pwd")
+	if ac.httpClient != c.httpClient {
+		t.Error("expected the derived client to share the transport")
+	}
+	if _, err := ac.ListBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.ListKeepers(); err != nil {
+		t.Fatal(err)
+	}
+	if addrs["/api/v0/lfs/list_buckets"] != alice || addrs["/api/v0/lfs/list_keepers"] != alice {
+		t.Errorf("expected the address of alice, got %v", addrs)
+	}
+
+	// an explicit option wins over the identity.
+	if _, err := ac.ListKeepers(SetAddress(bob)); err != nil {
+		t.Fatal(err)
+	}
+	if addrs["/api/v0/lfs/list_keepers"] != bob {
+		t.Errorf("expected the address of bob, got %q", addrs["/api/v0/lfs/list_keepers"])
+	}
+
+	// the password of the identity unlocks the user.
+	if err := ac.StartUser(alice); err != nil || password != "pwd" {
+		t.Errorf("expected the password of alice, got %q, %v", password, err)
+	}
+	if err := ac.StartUser(alice, SetPassword("other")); err != nil || password != "other" {
+		t.Errorf("expected the password option, got %q, %v", password, err)
+	}
+	if err := ac.StartUser(bob); err != nil || password != "" {
+		t.Errorf("expected no password for bob, got %q, %v", password, err)
+	}
+
+	// the context identity wins over the client one.
+	ctx := ContextWithIdentity(context.Background(), bob, "")
+	if _, err := ac.ListBucketsWithContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.BucketExistsWithContext(ctx, "bucket0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ac.ListKeepersWithContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for obj := range ac.ListObjectsWithContext(ctx, "bucket0", "", true) {
+		if obj.Err != nil {
+			t.Fatal(obj.Err)
+		}
+	}
+	if err := ac.StartUserWithContext(ctx, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"list_buckets", "head_Bucket", "list_keepers", "list_objects", "start"} {
+		if addr := addrs["/api/v0/lfs/"+path]; addr != bob {
+			t.Errorf("expected the address of bob for lfs/%s, got %q", path, addr)
+		}
+	}
+
+	// the parent client is unchanged.
+	if _, err := c.ListBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if addrs["/api/v0/lfs/list_buckets"] != "" {
+		t.Errorf("expected no address, got %q", addrs["/api/v0/lfs/list_buckets"])
+	}
+}
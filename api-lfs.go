@@ -3,7 +3,6 @@ package mefs
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"strconv"
 
 	peer "github.com/libp2p/go-libp2p-core/peer"
@@ -135,6 +134,7 @@ func (pl PeerList) String() string {
 	return res
 }
 
+// QueryEventType is the type of a DHT query event.
 type QueryEventType int
 
 const (
@@ -148,6 +148,8 @@ const (
 	DialingPeer
 )
 
+// QueryEvent is an event of a DHT query, Responses holds the peers
+// returned by PeerResponse and FinalPeer events.
 type QueryEvent struct {
 	ID        peer.ID
 	Type      QueryEventType
@@ -155,6 +157,19 @@ type QueryEvent struct {
 	Extra     string
 }
 
+// BlockLocation is a block of an object and the provider holding it.
+// Chunks from the data count of the bucket on are parity chunks.
+type BlockLocation struct {
+	BlockID  string
+	Stripe   int
+	Chunk    int
+	Provider string
+}
+
+type ObjectBlocks struct {
+	Blocks []BlockLocation
+}
+
 type GetBlockResult struct {
 	IsExist bool
 }
@@ -178,92 +193,171 @@ func (c Client) CreateUser(options ...LfsOpts) (*UserPrivMessage, error) {
 }
 
 func (c Client) StartUser(address string, options ...LfsOpts) error {
+	return c.StartUserWithContext(context.Background(), address, options...)
+}
+
+// StartUserWithContext - starts the lfs of the user address on the
+// node. An empty address starts the user of the identity carried by
+// ctx, else of the client; the password of that identity is sent
+// unless set with SetPassword.
+func (c Client) StartUserWithContext(ctx context.Context, address string, options ...LfsOpts) error {
+	if address == "" {
+		var err error
+		if address, err = c.address(ctx); err != nil {
+			return err
+		}
+	}
 	var res StringList
 	rb := c.Request("lfs/start", address)
+	if id, ok := c.identityOf(ctx); ok && id.Address == address && id.Password != "" {
+		rb.Option("password", id.Password)
+	}
 	for _, option := range options {
 		option(rb)
 	}
-	if err := rb.Exec(context.Background(), &res); err != nil {
+	if err := rb.Exec(ctx, &res); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (c Client) Fsync(options ...LfsOpts) error {
+	return c.FsyncWithContext(context.Background(), options...)
+}
+
+// FsyncWithContext - flushes the lfs metadata of the user, the
+// identity carried by ctx overrides the client one.
+func (c Client) FsyncWithContext(ctx context.Context, options ...LfsOpts) error {
 	var res StringList
-	rb := c.Request("lfs/fsync")
+	rb, err := c.lfsRequest(ctx, "lfs/fsync")
+	if err != nil {
+		return err
+	}
 	for _, option := range options {
 		option(rb)
 	}
 
-	if err := rb.Exec(context.Background(), &res); err != nil {
+	if err := rb.Exec(ctx, &res); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (c Client) ShowStorage(options ...LfsOpts) error {
+	return c.ShowStorageWithContext(context.Background(), options...)
+}
+
+// ShowStorageWithContext - shows the storage used by the user, the
+// identity carried by ctx overrides the client one.
+func (c Client) ShowStorageWithContext(ctx context.Context, options ...LfsOpts) error {
 	var res string
-	rb := c.Request("lfs/show_storage")
+	rb, err := c.lfsRequest(ctx, "lfs/show_storage")
+	if err != nil {
+		return err
+	}
 	for _, option := range options {
 		option(rb)
 	}
 
-	if err := rb.Exec(context.Background(), &res); err != nil {
+	if err := rb.Exec(ctx, &res); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (c Client) ListKeepers(options ...LfsOpts) (*PeerList, error) {
+	return c.ListKeepersWithContext(context.Background(), options...)
+}
+
+// ListKeepersWithContext - returns the keepers of the user, the
+// identity carried by ctx overrides the client one.
+func (c Client) ListKeepersWithContext(ctx context.Context, options ...LfsOpts) (*PeerList, error) {
 	var res *PeerList
-	rb := c.Request("lfs/list_keepers")
+	rb, err := c.lfsRequest(ctx, "lfs/list_keepers")
+	if err != nil {
+		return nil, err
+	}
 	for _, option := range options {
 		option(rb)
 	}
 
-	if err := rb.Exec(context.Background(), &res); err != nil {
+	if err := rb.Exec(ctx, &res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
-func (c Client) ChallengeTest(key, to string, options ...LfsOpts) (string, error) {
-	var res string
-	rb := c.Request("dht/challengeTest", key, to)
+func (c Client) ListProviders(options ...LfsOpts) (*PeerList, error) {
+	return c.ListProvidersWithContext(context.Background(), options...)
+}
+
+// ListProvidersWithContext - returns the providers of the user, the
+// identity carried by ctx overrides the client one.
+func (c Client) ListProvidersWithContext(ctx context.Context, options ...LfsOpts) (*PeerList, error) {
+	var res *PeerList
+	rb, err := c.lfsRequest(ctx, "lfs/list_providers")
+	if err != nil {
+		return nil, err
+	}
 	for _, option := range options {
 		option(rb)
 	}
 
-	if err := rb.Exec(context.Background(), &res); err != nil {
-		return "", err
+	if err := rb.Exec(ctx, &res); err != nil {
+		return nil, err
 	}
 	return res, nil
 }
 
+// GetFrom - returns the Value event of the query of key on id, see
+// GetFromStream for all the events.
 func (c Client) GetFrom(key, id string, options ...LfsOpts) (*QueryEvent, error) {
-	var res *QueryEvent
-	rb := c.Request("dht/getfrom", key, id)
-	for _, option := range options {
-		option(rb)
-	}
-
-	if err := rb.Exec(context.Background(), &res); err != nil {
+	events, err := c.GetFromStream(context.Background(), key, id, options...)
+	if err != nil {
 		return nil, err
 	}
-	return res, nil
+	return lastQueryEvent(events)
 }
 
 func (c Client) GetBlockFrom(key, id string, options ...LfsOpts) (string, error) {
-	fmt.Println("in GetBlockFrom")
+	return c.getBlockFrom(context.Background(), key, id, options...)
+}
+
+func (c Client) getBlockFrom(ctx context.Context, key, id string, options ...LfsOpts) (string, error) {
+	c.getLogger().Log(LogDebug, "get block from provider", Field{"key", key}, Field{"provider", id})
 	var res string
 	rb := c.Request("block/getfrom", key, id)
 	for _, option := range options {
 		option(rb)
 	}
 
-	if err := rb.Exec(context.Background(), &res); err != nil {
+	if err := rb.Exec(ctx, &res); err != nil {
 		return "", err
 	}
+	// the block returned by the provider is checked, not a copy of the
+	// local store which may not hold it.
+	if c.verifyBlocks {
+		if err := VerifyBlock(key, []byte(res)); err != nil && err != ErrUnverifiableKey {
+			return "", err
+		}
+	}
 	return res, nil
 }
+
+// ListObjectBlocks - returns the blocks of an object with their
+// providers, ordered by stripe then chunk.
+func (c Client) ListObjectBlocks(ctx context.Context, bucketName, objectName string, options ...LfsOpts) ([]BlockLocation, error) {
+	var res ObjectBlocks
+	rb, err := c.lfsRequest(ctx, "lfs/list_blocks", bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(rb)
+	}
+
+	if err := rb.Exec(ctx, &res); err != nil {
+		return nil, err
+	}
+	return res.Blocks, nil
+}
@@ -40,10 +40,19 @@ import (
 //   }
 //
 func (c Client) ListBuckets() ([]BucketInfo, error) {
+	return c.ListBucketsWithContext(context.Background())
+}
+
+// ListBucketsWithContext - lists the buckets of the user, the identity
+// carried by ctx overrides the client one.
+func (c Client) ListBucketsWithContext(ctx context.Context) ([]BucketInfo, error) {
 	var bks Buckets
-	rb := c.Request("lfs/list_buckets")
+	rb, err := c.lfsRequest(ctx, "lfs/list_buckets")
+	if err != nil {
+		return nil, err
+	}
 
-	if err := rb.Exec(context.Background(), &bks); err != nil {
+	if err := rb.Exec(ctx, &bks); err != nil {
 		return nil, err
 	}
 	res := make([]BucketInfo, 0, len(bks.Buckets))
@@ -285,6 +294,17 @@ func (c Client) listObjectsV2Query(bucketName, objectPrefix, continuationToken s
 //   }
 //
 func (c Client) ListObjects(bucketName, objectPrefix string, recursive bool, doneCh <-chan struct{}) <-chan ObjectInfo {
+	return c.listObjects(context.Background(), bucketName, objectPrefix, recursive, doneCh)
+}
+
+// ListObjectsWithContext - identical to ListObjects, the listing stops
+// when ctx is done and the identity carried by ctx overrides the
+// client one.
+func (c Client) ListObjectsWithContext(ctx context.Context, bucketName, objectPrefix string, recursive bool) <-chan ObjectInfo {
+	return c.listObjects(ctx, bucketName, objectPrefix, recursive, ctx.Done())
+}
+
+func (c Client) listObjects(ctx context.Context, bucketName, objectPrefix string, recursive bool, doneCh <-chan struct{}) <-chan ObjectInfo {
 	// Allocate new list objects channel.
 	objectStatCh := make(chan ObjectInfo, 1)
 	// Default listing is delimited at "/"
@@ -317,10 +337,13 @@ func (c Client) ListObjects(bucketName, objectPrefix string, recursive bool, don
 		var marker string
 		for {
 			// Get list of objects a maximum of 1000 per request.
-			result, err := c.listObjectsQuery(bucketName, objectPrefix, marker, delimiter, 1000)
+			result, err := c.listObjectsQuery(ctx, bucketName, objectPrefix, marker, delimiter, 1000)
 			if err != nil {
-				objectStatCh <- ObjectInfo{
+				select {
+				case objectStatCh <- ObjectInfo{
 					Err: err,
+				}:
+				case <-doneCh:
 				}
 				return
 			}
@@ -373,7 +396,7 @@ func (c Client) ListObjects(bucketName, objectPrefix string, recursive bool, don
 // ?delimiter - A delimiter is a character you use to group keys.
 // ?prefix - Limits the response to keys that begin with the specified prefix.
 // ?max-keys - Sets the maximum number of keys returned in the response body.
-func (c Client) listObjectsQuery(bucketName, objectPrefix, objectMarker, delimiter string, maxkeys int) (ListBucketResult, error) {
+func (c Client) listObjectsQuery(ctx context.Context, bucketName, objectPrefix, objectMarker, delimiter string, maxkeys int) (ListBucketResult, error) {
 	// Validate bucket name.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
 		return ListBucketResult{}, err
@@ -388,14 +411,12 @@ func (c Client) listObjectsQuery(bucketName, objectPrefix, objectMarker, delimit
 		maxkeys = 1000
 	}
 	var objs Objects
-	rb := c.Request("lfs/list_objects", bucketName)
-	creds, err := c.credsProvider.Get()
+	rb, err := c.lfsRequest(ctx, "lfs/list_objects", bucketName)
 	if err != nil {
 		return ListBucketResult{}, err
 	}
-	rb.Option("address", creds.AccessKeyID)
 	rb.Option("prefix", objectPrefix)
-	if err := rb.Exec(context.Background(), &objs); err != nil {
+	if err := rb.Exec(ctx, &objs); err != nil {
 		return ListBucketResult{}, err
 	}
 	var res ListBucketResult
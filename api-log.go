@@ -0,0 +1,109 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// LogRecord is a structured log event of the node. Fields holds the
+// entries of the event other than the well-known ones.
+type LogRecord struct {
+	Time      time.Time
+	Level     string
+	Subsystem string
+	Caller    string
+	Message   string
+	Fields    map[string]interface{}
+	Err       error
+}
+
+// UnmarshalJSON decodes both the current node log format, with
+// "logger", "msg" and "ts" keys, and the older one with "system",
+// "event" and "time" keys.
+func (l *LogRecord) UnmarshalJSON(b []byte) error {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	take := func(keys ...string) interface{} {
+		for _, k := range keys {
+			if v, ok := fields[k]; ok {
+				delete(fields, k)
+				return v
+			}
+		}
+		return nil
+	}
+	str := func(v interface{}) string {
+		s, _ := v.(string)
+		return s
+	}
+
+	l.Level = str(take("level"))
+	l.Subsystem = str(take("logger", "system"))
+	l.Caller = str(take("caller"))
+	l.Message = str(take("msg", "event", "message"))
+	switch ts := take("ts", "time").(type) {
+	case string:
+		l.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	case float64:
+		sec, frac := math.Modf(ts)
+		l.Time = time.Unix(int64(sec), int64(frac*1e9))
+	}
+	if len(fields) > 0 {
+		l.Fields = fields
+	}
+	return nil
+}
+
+// LogTail - streams the log events of the node until ctx is
+// cancelled. An error is sent as a record with Err set and the
+// channel is closed.
+func (c *Client) LogTail(ctx context.Context) (<-chan LogRecord, error) {
+	stream, err := c.Request("log/tail").Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan LogRecord)
+	stream.pump(func() interface{} { return new(LogRecord) }, func(v interface{}, err error) bool {
+		rec := *v.(*LogRecord)
+		rec.Err = err
+		select {
+		case out <- rec:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
+
+// LogLevel - sets the log level of a node subsystem, "*" or "all"
+// for every subsystem. The level is one of debug, info, warn, error,
+// dpanic, panic or fatal.
+func (c *Client) LogLevel(ctx context.Context, subsystem, level string) error {
+	if subsystem == "" || level == "" {
+		return ErrInvalidArgument("Subsystem and level cannot be empty.")
+	}
+	if subsystem == "*" {
+		subsystem = "all"
+	}
+	return c.Request("log/level", subsystem, level).Exec(ctx, nil)
+}
+
+// LogList - returns the sorted log subsystems of the node.
+func (c *Client) LogList(ctx context.Context) ([]string, error) {
+	var out struct {
+		Strings []string
+	}
+	if err := c.Request("log/ls").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	sort.Strings(out.Strings)
+	return out.Strings, nil
+}
@@ -0,0 +1,58 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNodeLog(t *testing.T) {
+	var level []string
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("log/tail", `{"level":"error","ts":"2020-05-01T10:00:00.5Z","logger":"lfs","caller":"lfs/put.go:42","msg":"put failed","bucket":"b0"}`+"\n"+
+		`{"event":"dial","system":"swarm","time":"2020-05-01T10:00:01Z"}`+"\n")
+	n.Handle("log/level", func(w http.ResponseWriter, r *http.Request) {
+		level = r.URL.Query()["arg"]
+		fmt.Fprint(w, `{"Message":"Changed log level of 'lfs' to 'debug'\n"}`)
+	})
+	n.Reply("log/ls", `{"Strings":["swarm","lfs","core"]}`)
+
+	c := n.Client()
+	ctx := context.Background()
+
+	recs, err := c.LogTail(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []LogRecord
+	for rec := range recs {
+		if rec.Err != nil {
+			t.Fatal(rec.Err)
+		}
+		got = append(got, rec)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(got))
+	}
+	if got[0].Level != "error" || got[0].Subsystem != "lfs" || got[0].Message != "put failed" ||
+		got[0].Fields["bucket"] != "b0" || got[0].Time.Nanosecond() != 5e8 {
+		t.Errorf("unexpected record %+v", got[0])
+	}
+	if got[1].Subsystem != "swarm" || got[1].Message != "dial" || got[1].Time.IsZero() {
+		t.Errorf("unexpected record %+v", got[1])
+	}
+
+	if err := c.LogLevel(ctx, "*", "debug"); err != nil {
+		t.Error(err)
+	}
+	if len(level) != 2 || level[0] != "all" || level[1] != "debug" {
+		t.Errorf("unexpected log level args %v", level)
+	}
+
+	subs, err := c.LogList(ctx)
+	if err != nil || len(subs) != 3 || subs[0] != "core" {
+		t.Errorf("unexpected subsystems %v, %v", subs, err)
+	}
+}
@@ -0,0 +1,111 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/memoio/mefs-sdk-go/pkg/credentials"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		opts       Options
+		shouldPass bool
+	}{
+		{Options{}, true},
+		{Options{Address: "0xD60457e090e166305D3CEE0BCF3778C689B7441d", Password: "123456"}, true},
+		{Options{BucketOptions: BucketOptions{Policy: 1, DataCount: 3, ParityCount: 2}}, true},
+		{Options{Timeout: time.Minute, Retry: RetryPolicy{MaxRetries: 3}}, true},
+
+		{Options{Address: "D60457e090e166305D3CEE0BCF3778C689B7441d"}, false},
+		{Options{Address: "0xZZ0457e090e166305D3CEE0BCF3778C689B7441d"}, false},
+		{Options{Address: "0xD60457e090e166305D3CEE0BCF3778C689B7441d", Creds: credentials.NewStaticV4("a", "b", "")}, false},
+		{Options{BucketOptions: BucketOptions{DataCount: -1}}, false},
+		{Options{Timeout: -time.Second}, false},
+		{Options{Retry: RetryPolicy{MaxRetries: -1}}, false},
+	}
+	for i, testCase := range testCases {
+		err := testCase.opts.validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+func TestNewWithOptionsAPIFile(t *testing.T) {
+	repo, err := ioutil.TempDir("", "mefs-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repo)
+
+	if _, err = NewWithOptions("", &Options{RepoPath: repo, UseAPIFile: true}); err == nil {
+		t.Fatal("expected error for missing api file")
+	}
+
+	if err = ioutil.WriteFile(path.Join(repo, DefaultApiFile), []byte("/ip4/127.0.0.1/tcp/5001\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewWithOptions("", &Options{RepoPath: repo, UseAPIFile: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.url != "127.0.0.1:5001" {
+		t.Errorf("expected endpoint 127.0.0.1:5001, got %s", c.url)
+	}
+
+	if _, err = NewWithOptions("", &Options{}); err == nil {
+		t.Fatal("expected error for empty endpoint")
+	}
+}
+
+func TestOptionsTimeout(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("version", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	n.Handle("log/tail", func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(w, `{"level":"info","ts":%d,"logger":"core","msg":"tick"}`+"\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+
+	c, err := NewWithOptions(n.Listener.Addr().String(), &Options{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = c.Version(); err == nil {
+		t.Error("expected the command to time out")
+	}
+
+	records, err := c.LogTail(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	for rec := range records {
+		if rec.Err != nil {
+			t.Fatalf("the stream was cut by the timeout: %v", rec.Err)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("expected 3 records, got %d", count)
+	}
+}
@@ -0,0 +1,154 @@
+package mefs
+
+import (
+	"context"
+	"sort"
+)
+
+type pinOutput struct {
+	Pins     []string
+	Progress int
+}
+
+// Pin - pins path so that the garbage collector of the node keeps
+// its blocks, recursive pins all the blocks it links to. It returns
+// the pinned keys.
+func (c *Client) Pin(ctx context.Context, path string, recursive bool) ([]string, error) {
+	var out pinOutput
+	err := c.Request("pin/add", path).
+		Option("recursive", recursive).
+		Exec(ctx, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Pins, nil
+}
+
+// PinProgress is an event of PinWithProgress. Progress counts the
+// blocks fetched so far, Pins is set by the last event.
+type PinProgress struct {
+	Progress int
+	Pins     []string
+	Err      error
+}
+
+// PinWithProgress - pins path recursively and streams the progress,
+// the channel is closed once the pin is done or failed.
+func (c *Client) PinWithProgress(ctx context.Context, path string) (<-chan PinProgress, error) {
+	stream, err := c.Request("pin/add", path).
+		Option("recursive", true).
+		Option("progress", true).
+		Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan PinProgress)
+	stream.pump(func() interface{} { return new(pinOutput) }, func(v interface{}, err error) bool {
+		po := v.(*pinOutput)
+		ev := PinProgress{Progress: po.Progress, Pins: po.Pins, Err: err}
+		select {
+		case out <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
+
+// Unpin - removes the pin of path and returns the unpinned keys.
+func (c *Client) Unpin(ctx context.Context, path string, recursive bool) ([]string, error) {
+	var out pinOutput
+	err := c.Request("pin/rm", path).
+		Option("recursive", recursive).
+		Exec(ctx, &out)
+	if err != nil {
+		return nil, err
+	}
+	return out.Pins, nil
+}
+
+// PinInfo is a pinned key and its pin type.
+type PinInfo struct {
+	Key  string
+	Type string
+}
+
+// PinLs - returns the pins of the given type, one of DirectPin,
+// RecursivePin, IndirectPin or AllPins, sorted by key.
+func (c *Client) PinLs(ctx context.Context, pinType string) ([]PinInfo, error) {
+	switch pinType {
+	case DirectPin, RecursivePin, IndirectPin, AllPins:
+	default:
+		return nil, ErrInvalidArgument("Invalid pin type " + pinType + ".")
+	}
+
+	var out struct {
+		Keys map[string]struct {
+			Type string
+		}
+	}
+	if err := c.Request("pin/ls").Option("type", pinType).Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+
+	pins := make([]PinInfo, 0, len(out.Keys))
+	for key, info := range out.Keys {
+		pins = append(pins, PinInfo{Key: key, Type: info.Type})
+	}
+	sort.Slice(pins, func(i, j int) bool { return pins[i].Key < pins[j].Key })
+	return pins, nil
+}
+
+// PinVerifyResult is the verification of a recursive pin, BadNodes
+// are its missing or unreadable blocks.
+type PinVerifyResult struct {
+	Key      string
+	Ok       bool
+	BadNodes []BlockResult
+}
+
+// pinVerifyOutput is the status of a pin sent by pin/verify.
+type pinVerifyOutput struct {
+	Cid       string
+	PinStatus struct {
+		Ok       bool
+		BadNodes []struct {
+			Cid string
+			Err string
+		}
+	}
+}
+
+// PinVerify - verifies that the blocks of the recursive pins are
+// present, results are streamed per pin and the channel is closed at
+// the end; a failure of the stream is sent as a result with Ok false
+// and no Key.
+func (c *Client) PinVerify(ctx context.Context) (<-chan PinVerifyResult, error) {
+	stream, err := c.Request("pin/verify").
+		Option("verbose", true).
+		Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan PinVerifyResult)
+	stream.pump(func() interface{} { return new(pinVerifyOutput) }, func(v interface{}, err error) bool {
+		pv := v.(*pinVerifyOutput)
+		res := PinVerifyResult{Key: pv.Cid, Ok: pv.PinStatus.Ok}
+		for _, bad := range pv.PinStatus.BadNodes {
+			res.BadNodes = append(res.BadNodes, BlockResult{Key: bad.Cid, Err: &Error{Command: "pin/verify", Message: bad.Err}})
+		}
+		if err != nil {
+			res = PinVerifyResult{BadNodes: []BlockResult{{Err: err}}}
+		}
+		select {
+		case out <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestPin(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("pin/add", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("progress") == "true" {
+			fmt.Fprint(w, `{"Progress":1}`+"\n"+`{"Progress":2}`+"\n")
+		}
+		fmt.Fprint(w, `{"Pins":["QmA"]}`+"\n")
+	})
+	n.Handle("pin/ls", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != RecursivePin {
+			t.Errorf("unexpected type %s", r.URL.Query().Get("type"))
+		}
+		fmt.Fprint(w, `{"Keys":{"QmB":{"Type":"recursive"},"QmA":{"Type":"recursive"}}}`)
+	})
+	n.Reply("pin/verify", `{"Cid":"QmA","PinStatus":{"Ok":true}}`+"\n"+
+		`{"Cid":"QmB","PinStatus":{"Ok":false,"BadNodes":[{"Cid":"QmC","Err":"merkledag: not found"}]}}`+"\n")
+
+	c := n.Client()
+	ctx := context.Background()
+
+	pins, err := c.Pin(ctx, "QmA", true)
+	if err != nil || len(pins) != 1 || pins[0] != "QmA" {
+		t.Fatalf("unexpected pins %v, %v", pins, err)
+	}
+
+	events, err := c.PinWithProgress(ctx, "QmA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last PinProgress
+	var count int
+	for ev := range events {
+		last = ev
+		count++
+	}
+	if count != 3 || last.Err != nil || len(last.Pins) != 1 {
+		t.Errorf("unexpected progress, %d events ending with %+v", count, last)
+	}
+
+	infos, err := c.PinLs(ctx, RecursivePin)
+	if err != nil || len(infos) != 2 || infos[0].Key != "QmA" || infos[1].Type != RecursivePin {
+		t.Errorf("unexpected pin list %+v, %v", infos, err)
+	}
+	if _, err = c.PinLs(ctx, "bogus"); err == nil {
+		t.Error("expected invalid pin type error")
+	}
+
+	results, err := c.PinVerify(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bad []PinVerifyResult
+	for res := range results {
+		if !res.Ok {
+			bad = append(bad, res)
+		}
+	}
+	if len(bad) != 1 || bad[0].Key != "QmB" || bad[0].BadNodes[0].Key != "QmC" {
+		t.Errorf("unexpected verify results %+v", bad)
+	}
+}
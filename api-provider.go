@@ -0,0 +1,163 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ProviderCapacity is the storage space of a provider node, in bytes.
+type ProviderCapacity struct {
+	// Capacity advertised to the keepers.
+	Capacity uint64
+	// Used by the blocks of the users.
+	Used uint64
+	// Free is the advertised capacity not used yet.
+	Free uint64
+}
+
+// ProviderOffer is the storage offer of a provider: the price of a
+// byte stored for a second and the terms of the offer.
+type ProviderOffer struct {
+	OfferID  string
+	Capacity uint64
+	Duration time.Duration
+	Price    *big.Int
+	Deposit  *big.Int
+	Start    time.Time
+}
+
+// UnmarshalJSON decodes the amounts, sent as JSON numbers or strings,
+// and the duration and start time, sent in seconds.
+func (o *ProviderOffer) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		OfferID  string
+		Capacity uint64
+		Duration int64
+		Price    json.RawMessage
+		Deposit  json.RawMessage
+		Start    int64
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	price, err := decodeAmount(raw.Price)
+	if err != nil {
+		return err
+	}
+	deposit, err := decodeAmount(raw.Deposit)
+	if err != nil {
+		return err
+	}
+
+	*o = ProviderOffer{
+		OfferID:  raw.OfferID,
+		Capacity: raw.Capacity,
+		Duration: time.Duration(raw.Duration) * time.Second,
+		Price:    price,
+		Deposit:  deposit,
+	}
+	if raw.Start > 0 {
+		o.Start = time.Unix(raw.Start, 0)
+	}
+	return nil
+}
+
+// ProviderUser is a user storing blocks on a provider.
+type ProviderUser struct {
+	Address string
+	Blocks  int
+	Size    uint64
+}
+
+// ProviderCapacity - returns the advertised and used space of the
+// provider node.
+func (c *Client) ProviderCapacity(ctx context.Context) (*ProviderCapacity, error) {
+	var out ProviderCapacity
+	if err := c.Request("provider/capacity").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	if out.Free == 0 && out.Capacity > out.Used {
+		out.Free = out.Capacity - out.Used
+	}
+	return &out, nil
+}
+
+// ProviderOffer - returns the storage offer of the provider node.
+func (c *Client) ProviderOffer(ctx context.Context) (*ProviderOffer, error) {
+	var out ProviderOffer
+	if err := c.Request("provider/offer").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ProviderUsers - returns the users storing blocks on the provider
+// node.
+func (c *Client) ProviderUsers(ctx context.Context) ([]ProviderUser, error) {
+	var out struct {
+		Users []ProviderUser
+	}
+	if err := c.Request("provider/list_users").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out.Users, nil
+}
+
+// ProviderBlocks - returns the blocks of user stored on the provider
+// node, or the blocks of every user when user is empty.
+func (c *Client) ProviderBlocks(ctx context.Context, user string) ([]BlockStat, error) {
+	if user != "" && !isValidAddress(user) {
+		return nil, ErrInvalidArgument("Invalid user address " + user + ".")
+	}
+
+	rb := c.Request("provider/list_blocks")
+	if user != "" {
+		rb.Option("address", user)
+	}
+	var out struct {
+		Blocks []BlockStat
+	}
+	if err := rb.Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out.Blocks, nil
+}
+
+// ProviderCapacity - see Client.ProviderCapacity.
+func (p ProviderAPI) ProviderCapacity(ctx context.Context) (*ProviderCapacity, error) {
+	return p.c.ProviderCapacity(ctx)
+}
+
+// ProviderOffer - see Client.ProviderOffer.
+func (p ProviderAPI) ProviderOffer(ctx context.Context) (*ProviderOffer, error) {
+	return p.c.ProviderOffer(ctx)
+}
+
+// ProviderUsers - see Client.ProviderUsers.
+func (p ProviderAPI) ProviderUsers(ctx context.Context) ([]ProviderUser, error) {
+	return p.c.ProviderUsers(ctx)
+}
+
+// ProviderBlocks - see Client.ProviderBlocks.
+func (p ProviderAPI) ProviderBlocks(ctx context.Context, user string) ([]BlockStat, error) {
+	return p.c.ProviderBlocks(ctx, user)
+}
+
+// decodeAmount - decodes an amount sent as a JSON number or as a
+// decimal string, nil when absent.
+func decodeAmount(raw json.RawMessage) (*big.Int, error) {
+	s := strings.Trim(string(raw), `"`)
+	if s == "" || s == "null" {
+		return nil, nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %s", raw)
+	}
+	return n, nil
+}
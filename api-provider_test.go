@@ -0,0 +1,60 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProvider(t *testing.T) {
+	const user = "0x0123456789abcdef0123456789abcdef01234567"
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("id", fmt.Sprintf(`{"ID":%q,"AgentVersion":"mefs/provider/0.4.22"}`, testPeerID))
+	n.Reply("provider/capacity", `{"Capacity":1000,"Used":250}`)
+	n.Reply("provider/offer", `{"OfferID":"0xabc","Capacity":1000,"Duration":86400,"Price":"123456789012345678901","Deposit":100,"Start":1588320000}`)
+	n.Reply("provider/list_users", fmt.Sprintf(`{"Users":[{"Address":%q,"Blocks":2,"Size":250}]}`, user))
+	n.Handle("provider/list_blocks", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("address") != user {
+			t.Errorf("unexpected address %q", r.URL.Query().Get("address"))
+		}
+		fmt.Fprint(w, `{"Blocks":[{"Key":"QmA","Size":200},{"Key":"QmB","Size":50}]}`)
+	})
+
+	c := n.Client()
+	ctx := context.Background()
+
+	p, err := c.AsProvider(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	capacity, err := p.ProviderCapacity(ctx)
+	if err != nil || capacity.Free != 750 {
+		t.Errorf("unexpected capacity %+v, %v", capacity, err)
+	}
+
+	offer, err := p.ProviderOffer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offer.Price.String() != "123456789012345678901" || offer.Deposit.Int64() != 100 ||
+		offer.Duration != 24*time.Hour || offer.Start.Unix() != 1588320000 {
+		t.Errorf("unexpected offer %+v", offer)
+	}
+
+	users, err := p.ProviderUsers(ctx)
+	if err != nil || len(users) != 1 || users[0].Address != user || users[0].Blocks != 2 {
+		t.Errorf("unexpected users %+v, %v", users, err)
+	}
+
+	blocks, err := p.ProviderBlocks(ctx, user)
+	if err != nil || len(blocks) != 2 || blocks[1].Key != "QmB" {
+		t.Errorf("unexpected blocks %+v, %v", blocks, err)
+	}
+	if _, err := p.ProviderBlocks(ctx, "alice"); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
@@ -0,0 +1,123 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+// PubSubMessage is a message received on a subscribed topic.
+type PubSubMessage struct {
+	From     peer.ID
+	Data     []byte
+	Seqno    uint64
+	TopicIDs []string
+	Err      error
+}
+
+// UnmarshalJSON decodes the message as sent by the node, the sender
+// and sequence number as base64 encoded bytes.
+func (m *PubSubMessage) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		From     []byte   `json:"from"`
+		Data     []byte   `json:"data"`
+		Seqno    []byte   `json:"seqno"`
+		TopicIDs []string `json:"topicIDs"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	if len(raw.From) > 0 {
+		from, err := peer.IDFromBytes(raw.From)
+		if err != nil {
+			return err
+		}
+		m.From = from
+	}
+	m.Data = raw.Data
+	m.TopicIDs = raw.TopicIDs
+	// sequence numbers are big-endian, at most 8 bytes.
+	m.Seqno = 0
+	for _, c := range raw.Seqno {
+		m.Seqno = m.Seqno<<8 | uint64(c)
+	}
+	return nil
+}
+
+// PubSubPublish - publishes data to topic.
+func (c *Client) PubSubPublish(ctx context.Context, topic string, data []byte) error {
+	if topic == "" {
+		return ErrInvalidArgument("Topic cannot be empty.")
+	}
+	return c.Request("pubsub/pub", topic, string(data)).Exec(ctx, nil)
+}
+
+// PubSubSubscribe - subscribes to topic and streams its messages
+// until ctx is cancelled. An error is sent as a message with Err set
+// and the channel is closed.
+func (c *Client) PubSubSubscribe(ctx context.Context, topic string) (<-chan PubSubMessage, error) {
+	if topic == "" {
+		return nil, ErrInvalidArgument("Topic cannot be empty.")
+	}
+
+	stream, err := c.Request("pubsub/sub", topic).Option("discover", true).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan PubSubMessage)
+	stream.pump(func() interface{} { return new(PubSubMessage) }, func(v interface{}, err error) bool {
+		msg := *v.(*PubSubMessage)
+		// the node sends an empty message once subscribed.
+		if err == nil && msg.From == "" && len(msg.Data) == 0 {
+			return true
+		}
+		msg.Err = err
+		select {
+		case out <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
+
+// PubSubLs - returns the topics the node is subscribed to.
+func (c *Client) PubSubLs(ctx context.Context) ([]string, error) {
+	var out struct {
+		Strings []string
+	}
+	if err := c.Request("pubsub/ls").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out.Strings, nil
+}
+
+// PubSubPeers - returns the peers the node is connected to on topic,
+// or on any topic when topic is empty.
+func (c *Client) PubSubPeers(ctx context.Context, topic string) ([]peer.ID, error) {
+	rb := c.Request("pubsub/peers")
+	if topic != "" {
+		rb.Arguments(topic)
+	}
+
+	var out struct {
+		Strings []string
+	}
+	if err := rb.Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+
+	peers := make([]peer.ID, 0, len(out.Strings))
+	for _, s := range out.Strings {
+		id, err := peer.Decode(s)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, id)
+	}
+	return peers, nil
+}
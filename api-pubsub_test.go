@@ -0,0 +1,73 @@
+package mefs
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"testing"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+func TestPubSub(t *testing.T) {
+	sender, err := peer.Decode(testPeerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.StdEncoding.EncodeToString
+
+	var published []string
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("pubsub/pub", func(w http.ResponseWriter, r *http.Request) {
+		published = r.URL.Query()["arg"]
+	})
+	n.Handle("pubsub/sub", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}\n")
+		for i := 1; i <= 2; i++ {
+			fmt.Fprintf(w, `{"from":%q,"data":%q,"seqno":%q,"topicIDs":["cache"]}`+"\n",
+				b64([]byte(sender)), b64([]byte(fmt.Sprintf("invalidate-%d", i))), b64([]byte{0, 0, 0, 0, 0, 0, 1, byte(i)}))
+		}
+	})
+	n.Reply("pubsub/ls", `{"Strings":["cache"]}`)
+	n.Reply("pubsub/peers", fmt.Sprintf(`{"Strings":[%q,%q]}`, testPeerID, testPeerID2))
+
+	c := n.Client()
+	ctx := context.Background()
+
+	if err := c.PubSubPublish(ctx, "cache", []byte("bucket/object")); err != nil {
+		t.Error(err)
+	}
+	if len(published) != 2 || published[0] != "cache" || published[1] != "bucket/object" {
+		t.Errorf("unexpected publish args %v", published)
+	}
+
+	msgs, err := c.PubSubSubscribe(ctx, "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []PubSubMessage
+	for msg := range msgs {
+		if msg.Err != nil {
+			t.Fatal(msg.Err)
+		}
+		got = append(got, msg)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(got))
+	}
+	if got[1].From != sender || string(got[1].Data) != "invalidate-2" || got[1].Seqno != 258 || got[1].TopicIDs[0] != "cache" {
+		t.Errorf("unexpected message %+v", got[1])
+	}
+
+	topics, err := c.PubSubLs(ctx)
+	if err != nil || len(topics) != 1 || topics[0] != "cache" {
+		t.Errorf("unexpected topics %v, %v", topics, err)
+	}
+
+	peers, err := c.PubSubPeers(ctx, "cache")
+	if err != nil || len(peers) != 2 || peers[0].Pretty() != testPeerID {
+		t.Errorf("unexpected peers %v, %v", peers, err)
+	}
+}
@@ -39,19 +39,26 @@ import (
 // For Amazon S3 for more supported regions - http://docs.aws.amazon.com/general/latest/gr/rande.html
 // For Google Cloud Storage for more supported regions - https://cloud.google.com/storage/docs/bucket-locations
 func (c Client) MakeBucket(bucketName string, location string) (err error) {
+	return c.MakeBucketWithContext(context.Background(), bucketName, location)
+}
+
+// MakeBucketWithContext - identical to MakeBucket, the identity carried
+// by ctx overrides the client one.
+func (c Client) MakeBucketWithContext(ctx context.Context, bucketName string, location string) (err error) {
 	// Validate the input arguments.
 	if err := s3utils.CheckValidBucketNameStrict(bucketName); err != nil {
 		return err
 	}
 
 	var bk Buckets
-	rb := c.Request("lfs/create_bucket", bucketName)
-	creds, err := c.credsProvider.Get()
+	rb, err := c.lfsRequest(ctx, "lfs/create_bucket", bucketName)
 	if err != nil {
 		return err
 	}
-	rb.Option("address", creds.AccessKeyID)
-	if err := rb.Exec(context.Background(), &bk); err != nil {
+	for _, option := range c.bucketOpts.lfsOpts() {
+		option(rb)
+	}
+	if err := rb.Exec(ctx, &bk); err != nil {
 		return err
 	}
 	return nil
@@ -385,18 +385,15 @@ func (c Client) putObjectDo(ctx context.Context, bucketName, objectName string,
 	slf := files.NewSliceDirectory([]files.DirEntry{files.FileEntry("", fr)})
 	fileReader := files.NewMultiFileReader(slf, true)
 	var objs Objects
-	rb := c.Request("lfs/put_object", bucketName)
-	creds, err := c.credsProvider.Get()
+	rb, err := c.lfsRequest(ctx, "lfs/put_object", bucketName)
 	if err != nil {
 		return ObjectInfo{}, err
 	}
-	rb.Option("address", creds.AccessKeyID)
 	rb.Option("objectname", objectName)
-	fmt.Println(rb.args, rb.opts)
+	c.getLogger().Log(LogDebug, "put object", Field{"bucket", bucketName}, Field{"object", objectName}, Field{"size", size})
 	rb = rb.Body(fileReader)
 	if err := rb.Exec(ctx, &objs); err != nil {
-		fmt.Println(bucketName, objectName, "错误", err)
-		fmt.Println("2", rb.args, rb.opts)
+		c.getLogger().Log(LogError, "put object failed", Field{"bucket", bucketName}, Field{"object", objectName}, Field{"err", err})
 		return ObjectInfo{}, err
 	}
 	t, _ := time.Parse(SHOWTIME, objs.Objects[0].Ctime)
@@ -0,0 +1,234 @@
+package mefs
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/memoio/mefs-sdk-go/pkg/s3utils"
+)
+
+// BlockStatus is the state of a block found by a repair check.
+type BlockStatus int
+
+// Block states.
+const (
+	BlockOK BlockStatus = iota
+	BlockMissing
+	BlockCorrupt
+	BlockRepaired
+)
+
+func (s BlockStatus) String() string {
+	switch s {
+	case BlockOK:
+		return "ok"
+	case BlockMissing:
+		return "missing"
+	case BlockCorrupt:
+		return "corrupt"
+	case BlockRepaired:
+		return "repaired"
+	}
+	return "unknown"
+}
+
+// BlockCheck is the state of a block on its provider.
+type BlockCheck struct {
+	BlockLocation
+	Status BlockStatus
+	Size   int
+	Err    error
+}
+
+// StripeReport holds the checks of the blocks of a stripe. A stripe
+// is recoverable while at least DataCount of its blocks are sound.
+type StripeReport struct {
+	Stripe      int
+	Blocks      []BlockCheck
+	Recoverable bool
+}
+
+// RepairReport is the result of RepairObject.
+type RepairReport struct {
+	Bucket      string
+	Object      string
+	DataCount   int
+	ParityCount int
+	DryRun      bool
+	Stripes     []StripeReport
+
+	// Damaged counts the missing and corrupt blocks, Repaired the
+	// ones reconstructed; RepairErrors holds the failed
+	// reconstructions by block.
+	Damaged      int
+	Repaired     int
+	RepairErrors map[string]error
+}
+
+// RepairOptions - options of RepairObject.
+type RepairOptions struct {
+	// DryRun only reports the damaged blocks.
+	DryRun bool
+
+	// Concurrency is the number of blocks checked at once,
+	// defaults to 4.
+	Concurrency int
+}
+
+// CheckObject - reports the missing and corrupt blocks of an object
+// without repairing them.
+func (c Client) CheckObject(ctx context.Context, bucketName, objectName string) (*RepairReport, error) {
+	return c.RepairObject(ctx, bucketName, objectName, RepairOptions{DryRun: true})
+}
+
+// RepairObject - fetches every block of an object from its provider
+// and verifies it, see checkBlock, then asks the node to reconstruct
+// the missing and corrupt blocks of the recoverable stripes from the
+// remaining data and parity blocks.
+func (c Client) RepairObject(ctx context.Context, bucketName, objectName string, opts RepairOptions) (*RepairReport, error) {
+	// Input validation.
+	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		return nil, err
+	}
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return nil, err
+	}
+
+	bucket, err := c.bucketStat(ctx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := c.ListObjectBlocks(ctx, bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RepairReport{
+		Bucket:       bucketName,
+		Object:       objectName,
+		DataCount:    int(bucket.DataCount),
+		ParityCount:  int(bucket.ParityCount),
+		DryRun:       opts.DryRun,
+		RepairErrors: make(map[string]error),
+	}
+
+	checks := make([]BlockCheck, len(blocks))
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, b := range blocks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, b BlockLocation) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			checks[i] = c.checkBlock(ctx, b)
+		}(i, b)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report.Stripes = groupStripes(checks, report.DataCount)
+	for s := range report.Stripes {
+		stripe := &report.Stripes[s]
+		for b := range stripe.Blocks {
+			check := &stripe.Blocks[b]
+			if check.Status == BlockOK {
+				continue
+			}
+			report.Damaged++
+			if opts.DryRun || !stripe.Recoverable {
+				continue
+			}
+			if err := c.RepairBlock(ctx, check.BlockID); err != nil {
+				report.RepairErrors[check.BlockID] = err
+				continue
+			}
+			check.Status = BlockRepaired
+			report.Repaired++
+		}
+	}
+	return report, nil
+}
+
+// RepairBlock - asks the node to reconstruct a block from the other
+// blocks of its stripe and to store it again.
+func (c Client) RepairBlock(ctx context.Context, blockID string, options ...LfsOpts) error {
+	rb, err := c.lfsRequest(ctx, "lfs/repair_block", blockID)
+	if err != nil {
+		return err
+	}
+	for _, option := range options {
+		option(rb)
+	}
+	return rb.Exec(ctx, nil)
+}
+
+// checkBlock - fetches a block from its provider and verifies it. A
+// block is missing when the provider fails to return it and corrupt
+// only when its content does not match its key; blocks whose key is
+// not a CID, like the internal block ids of LFS objects, cannot be
+// checked and are sound once returned.
+func (c Client) checkBlock(ctx context.Context, b BlockLocation) BlockCheck {
+	check := BlockCheck{BlockLocation: b}
+	data, err := c.getBlockFrom(ctx, b.BlockID, b.Provider)
+	if err == nil {
+		check.Size = len(data)
+		if err = VerifyBlock(b.BlockID, []byte(data)); err == ErrUnverifiableKey {
+			err = nil
+		}
+	}
+	if err != nil {
+		check.Status = BlockMissing
+		if _, ok := err.(*IntegrityError); ok {
+			check.Status = BlockCorrupt
+		}
+		check.Err = err
+	}
+	return check
+}
+
+// groupStripes - groups the checks by stripe, ordered by chunk.
+func groupStripes(checks []BlockCheck, dataCount int) []StripeReport {
+	byStripe := make(map[int][]BlockCheck)
+	for _, check := range checks {
+		byStripe[check.Stripe] = append(byStripe[check.Stripe], check)
+	}
+
+	stripes := make([]StripeReport, 0, len(byStripe))
+	for id, blocks := range byStripe {
+		sort.Slice(blocks, func(i, k int) bool { return blocks[i].Chunk < blocks[k].Chunk })
+
+		sound := 0
+		for _, b := range blocks {
+			if b.Status == BlockOK {
+				sound++
+			}
+		}
+		stripes = append(stripes, StripeReport{Stripe: id, Blocks: blocks, Recoverable: sound >= dataCount})
+	}
+	sort.Slice(stripes, func(i, k int) bool { return stripes[i].Stripe < stripes[k].Stripe })
+	return stripes
+}
+
+// bucketStat - returns the redundancy parameters of a bucket.
+func (c Client) bucketStat(ctx context.Context, bucketName string) (BucketStat, error) {
+	var bks Buckets
+	rb, err := c.lfsRequest(ctx, "lfs/head_Bucket", bucketName)
+	if err != nil {
+		return BucketStat{}, err
+	}
+	if err := rb.Exec(ctx, &bks); err != nil {
+		return BucketStat{}, err
+	}
+	if len(bks.Buckets) == 0 {
+		return BucketStat{}, ErrInvalidBucketName("Bucket " + bucketName + " not found.")
+	}
+	return bks.Buckets[0], nil
+}
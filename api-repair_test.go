@@ -0,0 +1,81 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+func TestRepairObject(t *testing.T) {
+	sum, err := mh.Sum([]byte("chunk"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := sum.B58String()
+
+	var mutex sync.Mutex
+	var repaired []string
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("lfs/head_Bucket", `{"Buckets":[{"BucketName":"bucket01","DataCount":2,"ParityCount":1}]}`)
+	n.Reply("lfs/list_blocks", `{"Blocks":[`+
+		`{"BlockID":"b_0_0","Stripe":0,"Chunk":0,"Provider":"p1"},`+
+		`{"BlockID":"b_0_1","Stripe":0,"Chunk":1,"Provider":"p2"},`+
+		`{"BlockID":"b_0_2","Stripe":0,"Chunk":2,"Provider":"p3"},`+
+		`{"BlockID":"b_1_0","Stripe":1,"Chunk":0,"Provider":"p1"},`+
+		`{"BlockID":"b_1_1","Stripe":1,"Chunk":1,"Provider":"p2"},`+
+		`{"BlockID":"`+key+`","Stripe":1,"Chunk":2,"Provider":"p3"}]}`)
+	n.Handle("block/getfrom", func(w http.ResponseWriter, r *http.Request) {
+		args := r.URL.Query()["arg"]
+		switch {
+		case args[1] == "p2":
+			nodeError(w, "block not found")
+		case args[0] == key:
+			fmt.Fprint(w, `"tampered"`)
+		default:
+			fmt.Fprint(w, `"chunk"`)
+		}
+	})
+	n.Handle("lfs/repair_block", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		repaired = append(repaired, r.URL.Query().Get("arg"))
+		mutex.Unlock()
+	})
+
+	c := n.Client()
+
+	report, err := c.CheckObject(context.Background(), "bucket01", "object01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Damaged != 3 || report.Repaired != 0 || len(repaired) != 0 {
+		t.Fatalf("unexpected dry run report %+v, repaired %v", report, repaired)
+	}
+	if len(report.Stripes) != 2 || !report.Stripes[0].Recoverable || report.Stripes[1].Recoverable {
+		t.Fatalf("unexpected stripes %+v", report.Stripes)
+	}
+	if s := report.Stripes[1].Blocks[2].Status; s != BlockCorrupt {
+		t.Errorf("expected corrupt block, got %v", s)
+	}
+	if s := report.Stripes[0].Blocks[1].Status; s != BlockMissing {
+		t.Errorf("expected missing block, got %v", s)
+	}
+	if b := report.Stripes[0].Blocks[0]; b.Status != BlockOK || b.Size != 5 {
+		t.Errorf("expected sound block, got %+v", b)
+	}
+
+	report, err = c.RepairObject(context.Background(), "bucket01", "object01", RepairOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Repaired != 1 || len(repaired) != 1 || repaired[0] != "b_0_1" {
+		t.Errorf("expected b_0_1 repaired, got %v", repaired)
+	}
+	if s := report.Stripes[0].Blocks[1].Status; s != BlockRepaired {
+		t.Errorf("expected repaired block, got %v", s)
+	}
+}
@@ -0,0 +1,100 @@
+package mefs
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RepoStat is the storage state of the node repository.
+type RepoStat struct {
+	NumObjects uint64
+	RepoSize   uint64
+	StorageMax uint64
+	RepoPath   string
+	Version    string
+}
+
+// RepoStat - returns the statistics of the node repository.
+func (c *Client) RepoStat(ctx context.Context) (*RepoStat, error) {
+	var out RepoStat
+	if err := c.Request("repo/stat").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// gcOutput is a key removed by repo/gc.
+type gcOutput struct {
+	Key   json.RawMessage
+	Error string
+}
+
+// RepoGC - runs the garbage collector of the node and streams the
+// removed keys. Errors are sent as results with Err set, the channel
+// is closed at the end of the collection.
+func (c *Client) RepoGC(ctx context.Context) (<-chan BlockResult, error) {
+	stream, err := c.Request("repo/gc").Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan BlockResult)
+	stream.pump(func() interface{} { return new(gcOutput) }, func(v interface{}, err error) bool {
+		gc := v.(*gcOutput)
+		res := BlockResult{Key: decodeLinkKey(gc.Key), Err: err}
+		if err == nil && gc.Error != "" {
+			res.Err = &Error{Command: "repo/gc", Message: gc.Error}
+		}
+		select {
+		case out <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
+
+// RepoVerifyProgress is an event of RepoVerify, Msg reports a corrupt
+// block and Progress the number of blocks verified so far.
+type RepoVerifyProgress struct {
+	Msg      string
+	Progress int
+	Err      error
+}
+
+// RepoVerify - verifies all the blocks of the node repository and
+// streams the progress, the channel is closed at the end.
+func (c *Client) RepoVerify(ctx context.Context) (<-chan RepoVerifyProgress, error) {
+	stream, err := c.Request("repo/verify").Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan RepoVerifyProgress)
+	stream.pump(func() interface{} { return new(RepoVerifyProgress) }, func(v interface{}, err error) bool {
+		ev := *v.(*RepoVerifyProgress)
+		ev.Err = err
+		select {
+		case out <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
+
+// decodeLinkKey - returns the key of a JSON link, {"/": key}, or of a
+// plain JSON string.
+func decodeLinkKey(raw json.RawMessage) string {
+	var link struct {
+		Key string `json:"/"`
+	}
+	if err := json.Unmarshal(raw, &link); err == nil && link.Key != "" {
+		return link.Key
+	}
+	var key string
+	json.Unmarshal(raw, &key)
+	return key
+}
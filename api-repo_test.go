@@ -0,0 +1,57 @@
+package mefs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRepoMaintenance(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("repo/stat", `{"NumObjects":3,"RepoSize":4096,"StorageMax":10000000000,"RepoPath":"/root/.mefs","Version":"fs-repo@7"}`)
+	n.Reply("repo/gc", `{"Key":{"/":"QmA"}}`+"\n"+`{"Key":{"/":"QmB"}}`+"\n"+`{"Error":"could not remove QmC"}`+"\n")
+	n.Reply("repo/verify", `{"Progress":1}`+"\n"+`{"Msg":"block QmD was corrupt"}`+"\n"+`{"Progress":2}`+"\n")
+
+	c := n.Client()
+	ctx := context.Background()
+
+	stat, err := c.RepoStat(ctx)
+	if err != nil || stat.NumObjects != 3 || stat.RepoSize != 4096 || stat.Version != "fs-repo@7" {
+		t.Fatalf("unexpected repo stat %+v, %v", stat, err)
+	}
+
+	removed, err := c.RepoGC(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	var errs int
+	for res := range removed {
+		if res.Err != nil {
+			errs++
+			continue
+		}
+		keys = append(keys, res.Key)
+	}
+	if len(keys) != 2 || keys[1] != "QmB" || errs != 1 {
+		t.Errorf("unexpected gc results %v, %d errors", keys, errs)
+	}
+
+	progress, err := c.RepoVerify(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	var last int
+	for ev := range progress {
+		if ev.Msg != "" {
+			msgs = append(msgs, ev.Msg)
+		}
+		if ev.Progress > 0 {
+			last = ev.Progress
+		}
+	}
+	if len(msgs) != 1 || last != 2 {
+		t.Errorf("unexpected verify progress %v, last %d", msgs, last)
+	}
+}
@@ -0,0 +1,265 @@
+package mefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Roles of MEFS nodes, keepers and providers are also the roles of
+// the storage peers of a user.
+const (
+	RoleUser     = "user"
+	RoleKeeper   = "keeper"
+	RoleProvider = "provider"
+)
+
+// ErrUnknownRole is returned when the role of a node can neither be
+// read from its agent version nor from the role command.
+var ErrUnknownRole = errors.New("mefs: unknown node role")
+
+// commandRoles - command prefixes only valid on nodes of a role.
+var commandRoles = map[string]string{
+	"lfs/":      RoleUser,
+	"keeper/":   RoleKeeper,
+	"provider/": RoleProvider,
+}
+
+// WrongRoleError is returned when a command is sent to a node of a
+// role it is not valid for.
+type WrongRoleError struct {
+	Command string
+	Role    string // role of the node
+	Want    string // role the command needs
+}
+
+func (e *WrongRoleError) Error() string {
+	return fmt.Sprintf("mefs: command %s needs a %s node, node is a %s", e.Command, e.Want, e.Role)
+}
+
+// commandRole - returns the role command is valid for, empty when it
+// is valid for every role.
+func commandRole(command string) string {
+	for prefix, role := range commandRoles {
+		if strings.HasPrefix(command, prefix) {
+			return role
+		}
+	}
+	return ""
+}
+
+// ParseRole - returns the role found in an agent version such as
+// "mefs/keeper/0.4.22", empty when there is none.
+func ParseRole(agentVersion string) string {
+	fields := strings.FieldsFunc(strings.ToLower(agentVersion), func(r rune) bool {
+		return r == '/' || r == '-' || r == ' ' || r == ':'
+	})
+	for _, f := range fields {
+		switch f {
+		case RoleUser, RoleKeeper, RoleProvider:
+			return f
+		}
+	}
+	return ""
+}
+
+// Role - returns the role of the node, read from its agent version
+// or, for nodes not advertising it, from the role command.
+func (c *Client) Role(ctx context.Context) (string, error) {
+	var id IdOutput
+	if err := c.Request("id").Exec(ctx, &id); err != nil {
+		return "", err
+	}
+	if role := ParseRole(id.AgentVersion); role != "" {
+		return role, nil
+	}
+
+	var out struct {
+		Role string
+	}
+	if err := c.Request("role").Exec(ctx, &out); err != nil {
+		if _, ok := err.(*Error); ok {
+			return "", ErrUnknownRole
+		}
+		return "", err
+	}
+	if role := ParseRole(out.Role); role != "" {
+		return role, nil
+	}
+	return "", ErrUnknownRole
+}
+
+// withRole - returns a copy of c sharing its transport which fails
+// the commands not valid for role with a *WrongRoleError.
+func (c *Client) withRole(role string) *Client {
+	rc := *c
+	n := len(c.interceptors)
+	rc.interceptors = append(c.interceptors[:n:n], func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		if want := commandRole(req.Command); want != "" && want != role {
+			return nil, &WrongRoleError{Command: req.Command, Role: role, Want: want}
+		}
+		return next(ctx, req)
+	})
+	return &rc
+}
+
+// asRole - detects the role of the node and returns the client bound
+// to it, a *WrongRoleError when it is not want.
+func (c *Client) asRole(ctx context.Context, want string) (*Client, error) {
+	role, err := c.Role(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if role != want {
+		return nil, &WrongRoleError{Role: role, Want: want}
+	}
+	return c.withRole(role), nil
+}
+
+// NodeAPI holds the calls valid on nodes of every role.
+type NodeAPI struct {
+	c *Client
+}
+
+// ID - returns the identity of the node.
+func (n NodeAPI) ID() (*IdOutput, error) { return n.c.ID() }
+
+// Version - returns the version and commit of the node.
+func (n NodeAPI) Version() (string, string, error) { return n.c.Version() }
+
+// Diagnose - returns the health report of the node.
+func (n NodeAPI) Diagnose(ctx context.Context) *NodeHealth { return n.c.Diagnose(ctx) }
+
+// SwarmPeers - returns the swarm connections of the node.
+func (n NodeAPI) SwarmPeers(ctx context.Context) (*SwarmConnInfos, error) { return n.c.SwarmPeers(ctx) }
+
+// RepoStat - returns the statistics of the node repository.
+func (n NodeAPI) RepoStat(ctx context.Context) (*RepoStat, error) { return n.c.RepoStat(ctx) }
+
+// Request - returns a builder for any command, commands of another
+// role fail with a *WrongRoleError.
+func (n NodeAPI) Request(command string, args ...string) *RequestBuilder {
+	return n.c.Request(command, args...)
+}
+
+// UserAPI is the client of a user node, the storage consumer.
+type UserAPI struct {
+	NodeAPI
+}
+
+// AsUser - returns the user facade of the node, a *WrongRoleError
+// when the node is not a user.
+func (c *Client) AsUser(ctx context.Context) (*UserAPI, error) {
+	rc, err := c.asRole(ctx, RoleUser)
+	if err != nil {
+		return nil, err
+	}
+	return &UserAPI{NodeAPI{rc}}, nil
+}
+
+// MakeBucket - creates a bucket, see Client.MakeBucket.
+func (u UserAPI) MakeBucket(bucketName string) error { return u.c.MakeBucket(bucketName, "") }
+
+// BucketExists - see Client.BucketExists.
+func (u UserAPI) BucketExists(bucketName string) (bool, error) { return u.c.BucketExists(bucketName) }
+
+// ListBuckets - see Client.ListBuckets.
+func (u UserAPI) ListBuckets() ([]BucketInfo, error) { return u.c.ListBuckets() }
+
+// RemoveBucket - see Client.RemoveBucket.
+func (u UserAPI) RemoveBucket(bucketName string) error { return u.c.RemoveBucket(bucketName) }
+
+// PutObject - see Client.PutObjectWithContext.
+func (u UserAPI) PutObject(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize int64, opts PutObjectOptions) (int64, error) {
+	return u.c.PutObjectWithContext(ctx, bucketName, objectName, reader, objectSize, opts)
+}
+
+// GetObject - see Client.GetObjectWithContext.
+func (u UserAPI) GetObject(ctx context.Context, bucketName, objectName string, opts GetObjectOptions) (*Object, error) {
+	return u.c.GetObjectWithContext(ctx, bucketName, objectName, opts)
+}
+
+// StatObject - see Client.StatObject.
+func (u UserAPI) StatObject(bucketName, objectName string, opts StatObjectOptions) (ObjectInfo, error) {
+	return u.c.StatObject(bucketName, objectName, opts)
+}
+
+// ListObjects - see Client.ListObjects.
+func (u UserAPI) ListObjects(bucketName, objectPrefix string, recursive bool, doneCh <-chan struct{}) <-chan ObjectInfo {
+	return u.c.ListObjects(bucketName, objectPrefix, recursive, doneCh)
+}
+
+// RemoveObject - see Client.RemoveObject.
+func (u UserAPI) RemoveObject(bucketName, objectName string) error {
+	return u.c.RemoveObject(bucketName, objectName)
+}
+
+// ListKeepers - see Client.ListKeepers.
+func (u UserAPI) ListKeepers(options ...LfsOpts) (*PeerList, error) { return u.c.ListKeepers(options...) }
+
+// ListProviders - see Client.ListProviders.
+func (u UserAPI) ListProviders(options ...LfsOpts) (*PeerList, error) {
+	return u.c.ListProviders(options...)
+}
+
+// Fsync - see Client.Fsync.
+func (u UserAPI) Fsync(options ...LfsOpts) error { return u.c.Fsync(options...) }
+
+// StoragePeersHealth - see Client.StoragePeersHealth.
+func (u UserAPI) StoragePeersHealth(ctx context.Context, options ...LfsOpts) ([]PeerHealth, error) {
+	return u.c.StoragePeersHealth(ctx, options...)
+}
+
+// CheckObject - see Client.CheckObject.
+func (u UserAPI) CheckObject(ctx context.Context, bucketName, objectName string) (*RepairReport, error) {
+	return u.c.CheckObject(ctx, bucketName, objectName)
+}
+
+// RepairObject - see Client.RepairObject.
+func (u UserAPI) RepairObject(ctx context.Context, bucketName, objectName string, opts RepairOptions) (*RepairReport, error) {
+	return u.c.RepairObject(ctx, bucketName, objectName, opts)
+}
+
+// AuditBucket - see Client.AuditBucket.
+func (u UserAPI) AuditBucket(ctx context.Context, bucketName string, opts AuditOptions) (*AuditReport, error) {
+	return u.c.AuditBucket(ctx, bucketName, opts)
+}
+
+// KeeperAPI is the client of a keeper node, which manages users and
+// challenges their providers.
+type KeeperAPI struct {
+	NodeAPI
+}
+
+// AsKeeper - returns the keeper facade of the node, a
+// *WrongRoleError when the node is not a keeper.
+func (c *Client) AsKeeper(ctx context.Context) (*KeeperAPI, error) {
+	rc, err := c.asRole(ctx, RoleKeeper)
+	if err != nil {
+		return nil, err
+	}
+	return &KeeperAPI{NodeAPI{rc}}, nil
+}
+
+// ChallengeTest - see Client.ChallengeTest.
+func (k KeeperAPI) ChallengeTest(key, to string, options ...LfsOpts) (*ChallengeResult, error) {
+	return k.c.ChallengeTest(key, to, options...)
+}
+
+// ProviderAPI is the client of a provider node, which stores the
+// blocks of users.
+type ProviderAPI struct {
+	NodeAPI
+}
+
+// AsProvider - returns the provider facade of the node, a
+// *WrongRoleError when the node is not a provider.
+func (c *Client) AsProvider(ctx context.Context) (*ProviderAPI, error) {
+	rc, err := c.asRole(ctx, RoleProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderAPI{NodeAPI{rc}}, nil
+}
@@ -0,0 +1,71 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseRole(t *testing.T) {
+	testCases := []struct {
+		agent string
+		role  string
+	}{
+		{"mefs/keeper/0.4.22", RoleKeeper},
+		{"mefs-provider/1.0", RoleProvider},
+		{"MEFS User 1.0", RoleUser},
+		{"go-ipfs/0.4.22/", ""},
+		{"", ""},
+	}
+	for _, tc := range testCases {
+		if role := ParseRole(tc.agent); role != tc.role {
+			t.Errorf("ParseRole(%q) = %q, want %q", tc.agent, role, tc.role)
+		}
+	}
+}
+
+func TestRoleFacades(t *testing.T) {
+	agent := "mefs/keeper/0.4.22"
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("id", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ID":%q,"AgentVersion":%q}`, testPeerID, agent)
+	})
+	n.Reply("role", `{"Role":"provider"}`)
+	n.Reply("lfs/list_buckets", `{}`)
+
+	c := n.Client()
+	ctx := context.Background()
+
+	if _, err := c.AsUser(ctx); err == nil {
+		t.Error("expected an error for a user facade of a keeper")
+	} else if wr, ok := err.(*WrongRoleError); !ok || wr.Role != RoleKeeper || wr.Want != RoleUser {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	k, err := c.AsKeeper(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := k.ID(); err != nil || id.AgentVersion != agent {
+		t.Errorf("unexpected id %+v, %v", id, err)
+	}
+	err = k.Request("lfs/list_buckets").Exec(ctx, nil)
+	if wr, ok := err.(*WrongRoleError); !ok || wr.Command != "lfs/list_buckets" {
+		t.Errorf("expected a wrong role error, got %v", err)
+	}
+	if calls := n.Calls("lfs/list_buckets"); calls != 0 {
+		t.Errorf("expected no lfs command sent, got %d", calls)
+	}
+
+	// the facade does not restrict the client it was built from.
+	if err := c.Request("lfs/list_buckets").Exec(ctx, nil); err != nil {
+		t.Error(err)
+	}
+
+	agent = "go-ipfs/0.4.22"
+	if role, err := c.Role(ctx); err != nil || role != RoleProvider {
+		t.Errorf("unexpected role %q, %v", role, err)
+	}
+}
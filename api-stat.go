@@ -27,20 +27,24 @@ import (
 
 // BucketExists verify if bucket exists and you have permission to access it.
 func (c Client) BucketExists(bucketName string) (bool, error) {
+	return c.BucketExistsWithContext(context.Background(), bucketName)
+}
+
+// BucketExistsWithContext - identical to BucketExists, the identity
+// carried by ctx overrides the client one.
+func (c Client) BucketExistsWithContext(ctx context.Context, bucketName string) (bool, error) {
 	// Input validation.
 	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
 		return false, err
 	}
 
 	var bks Buckets
-	rb := c.Request("lfs/head_Bucket", bucketName)
-	creds, err := c.credsProvider.Get()
+	rb, err := c.lfsRequest(ctx, "lfs/head_Bucket", bucketName)
 	if err != nil {
 		return false, err
 	}
-	rb.Option("address", creds.AccessKeyID)
 
-	if err := rb.Exec(context.Background(), &bks); err != nil {
+	if err := rb.Exec(ctx, &bks); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -99,12 +103,10 @@ func (c Client) statObject(ctx context.Context, bucketName, objectName string, o
 		return ObjectInfo{}, err
 	}
 
-	rb := c.Request("lfs/head_object", bucketName, objectName)
-	creds, err := c.credsProvider.Get()
+	rb, err := c.lfsRequest(ctx, "lfs/head_object", bucketName, objectName)
 	if err != nil {
 		return ObjectInfo{}, err
 	}
-	rb.Option("address", creds.AccessKeyID)
 	var objs Objects
 	if err := rb.Exec(ctx, &objs); err != nil {
 		return ObjectInfo{}, err
@@ -0,0 +1,66 @@
+package mefs
+
+import (
+	"context"
+	"time"
+)
+
+// BandwidthStats is a snapshot of the bandwidth used by the node, the
+// totals are in bytes and the rates in bytes per second.
+type BandwidthStats struct {
+	TotalIn  int64
+	TotalOut int64
+	RateIn   float64
+	RateOut  float64
+	Err      error `json:"-"`
+}
+
+// StatsBWOptions filters the bandwidth statistics to a peer or to a
+// protocol. With a non-zero Interval the node sends a snapshot at
+// each interval until the context is cancelled.
+type StatsBWOptions struct {
+	Peer     string
+	Protocol string
+	Interval time.Duration
+}
+
+// StatsBW - streams the bandwidth statistics of the node. Snapshots
+// are decoded as the node sends them, an error is sent as a snapshot
+// with Err set and the channel is closed.
+func (c *Client) StatsBW(ctx context.Context, opts StatsBWOptions) (<-chan BandwidthStats, error) {
+	if opts.Peer != "" && opts.Protocol != "" {
+		return nil, ErrInvalidArgument("Only one of peer or protocol can be given.")
+	}
+	if opts.Interval < 0 {
+		return nil, ErrInvalidArgument("Interval cannot be negative.")
+	}
+
+	rb := c.Request("stats/bw")
+	if opts.Peer != "" {
+		rb.Option("peer", opts.Peer)
+	}
+	if opts.Protocol != "" {
+		rb.Option("proto", opts.Protocol)
+	}
+	if opts.Interval > 0 {
+		rb.Option("poll", true).Option("interval", opts.Interval.String())
+	}
+
+	stream, err := rb.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan BandwidthStats)
+	stream.pump(func() interface{} { return new(BandwidthStats) }, func(v interface{}, err error) bool {
+		bw := *v.(*BandwidthStats)
+		bw.Err = err
+		select {
+		case out <- bw:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatsBW(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("stats/bw", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("poll") != "true" || q.Get("interval") != "10ms" || q.Get("proto") != "/ipfs/bitswap" {
+			t.Errorf("unexpected query %v", q)
+		}
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, `{"TotalIn":%d,"TotalOut":%d,"RateIn":%d.5,"RateOut":1}`+"\n", i*100, i*10, i)
+			w.(http.Flusher).Flush()
+		}
+	})
+
+	c := n.Client()
+
+	if _, err := c.StatsBW(context.Background(), StatsBWOptions{Peer: testPeerID, Protocol: "/ipfs/bitswap"}); err == nil {
+		t.Error("expected an error for peer and protocol filters")
+	}
+
+	snaps, err := c.StatsBW(context.Background(), StatsBWOptions{Protocol: "/ipfs/bitswap", Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []BandwidthStats
+	for bw := range snaps {
+		if bw.Err != nil {
+			t.Fatal(bw.Err)
+		}
+		got = append(got, bw)
+	}
+	if len(got) != 3 || got[2].TotalIn != 300 || got[2].TotalOut != 30 || got[1].RateIn != 2.5 {
+		t.Errorf("unexpected snapshots %+v", got)
+	}
+}
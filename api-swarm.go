@@ -0,0 +1,192 @@
+package mefs
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// SwarmConnection is a parsed swarm connection to a peer.
+type SwarmConnection struct {
+	Addr ma.Multiaddr
+	Peer peer.ID
+
+	// Latency is zero when the node has no measurement.
+	Latency time.Duration
+	Muxer   string
+	Streams []string
+}
+
+// LatencyDuration - parses the latency reported by the node, ok is
+// false when it has no measurement ("n/a").
+func (ci SwarmConnInfo) LatencyDuration() (d time.Duration, ok bool) {
+	d, err := time.ParseDuration(ci.Latency)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// SwarmConnections - returns the swarm connections of the node with
+// their latency and streams.
+func (c *Client) SwarmConnections(ctx context.Context) ([]SwarmConnection, error) {
+	var infos SwarmConnInfos
+	err := c.Request("swarm/peers").
+		Option("latency", true).
+		Option("streams", true).
+		Option("verbose", true).
+		Exec(ctx, &infos)
+	if err != nil {
+		return nil, err
+	}
+
+	conns := make([]SwarmConnection, 0, len(infos.Peers))
+	for _, info := range infos.Peers {
+		addr, err := ma.NewMultiaddr(info.Addr)
+		if err != nil {
+			return nil, err
+		}
+		id, err := peer.Decode(info.Peer)
+		if err != nil {
+			return nil, err
+		}
+		conn := SwarmConnection{Addr: addr, Peer: id, Muxer: info.Muxer}
+		conn.Latency, _ = info.LatencyDuration()
+		for _, s := range info.Streams {
+			conn.Streams = append(conn.Streams, s.Protocol)
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// SwarmDisconnect closes the swarm connections to the given addresses.
+func (c *Client) SwarmDisconnect(ctx context.Context, addr ...string) error {
+	var conn *swarmConnection
+	return c.Request("swarm/disconnect").
+		Arguments(addr...).
+		Exec(ctx, &conn)
+}
+
+// SwarmAddrs - returns the addresses the node listens on.
+func (c *Client) SwarmAddrs(ctx context.Context) ([]ma.Multiaddr, error) {
+	var out swarmConnection
+	if err := c.Request("swarm/addrs/listen").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return parseMultiaddrs(out.Strings)
+}
+
+// SwarmPeeringLs - returns the peers the node keeps connections to.
+func (c *Client) SwarmPeeringLs(ctx context.Context) ([]peer.AddrInfo, error) {
+	var out struct {
+		Peers []peer.AddrInfo
+	}
+	if err := c.Request("swarm/peering/ls").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out.Peers, nil
+}
+
+// SwarmPeeringAdd - adds peers, given as multiaddrs ending with
+// /p2p/<peer id>, to the peers the node keeps connections to.
+func (c *Client) SwarmPeeringAdd(ctx context.Context, addr ...string) error {
+	return c.Request("swarm/peering/add").Arguments(addr...).Exec(ctx, nil)
+}
+
+// SwarmPeeringRm - removes peers from the peers the node keeps
+// connections to.
+func (c *Client) SwarmPeeringRm(ctx context.Context, peerID ...string) error {
+	return c.Request("swarm/peering/rm").Arguments(peerID...).Exec(ctx, nil)
+}
+
+type bootstrapPeers struct {
+	Peers []string
+}
+
+// BootstrapList - returns the bootstrap peers of the node.
+func (c *Client) BootstrapList(ctx context.Context) ([]ma.Multiaddr, error) {
+	var out bootstrapPeers
+	if err := c.Request("bootstrap/list").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return parseMultiaddrs(out.Peers)
+}
+
+// BootstrapAdd - adds bootstrap peers and returns the added ones.
+func (c *Client) BootstrapAdd(ctx context.Context, addr ...string) ([]ma.Multiaddr, error) {
+	var out bootstrapPeers
+	if err := c.Request("bootstrap/add").Arguments(addr...).Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return parseMultiaddrs(out.Peers)
+}
+
+// BootstrapRm - removes bootstrap peers and returns the removed ones.
+func (c *Client) BootstrapRm(ctx context.Context, addr ...string) ([]ma.Multiaddr, error) {
+	var out bootstrapPeers
+	if err := c.Request("bootstrap/rm").Arguments(addr...).Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return parseMultiaddrs(out.Peers)
+}
+
+// BootstrapRmAll - removes all the bootstrap peers.
+func (c *Client) BootstrapRmAll(ctx context.Context) ([]ma.Multiaddr, error) {
+	var out bootstrapPeers
+	if err := c.Request("bootstrap/rm/all").Exec(ctx, &out); err != nil {
+		return nil, err
+	}
+	return parseMultiaddrs(out.Peers)
+}
+
+// PingResult is a ping reply, or the final summary of a ping when
+// Time is zero and Text is set.
+type PingResult struct {
+	Success bool
+	Time    time.Duration
+	Text    string
+}
+
+// Ping - sends count pings to peerID and streams the round-trip
+// times. The channel is closed once the ping is over, failures are
+// sent as results with Success false.
+func (c *Client) Ping(ctx context.Context, peerID string, count int) (<-chan PingResult, error) {
+	stream, err := c.Request("ping", peerID).
+		Option("count", strconv.Itoa(count)).
+		Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan PingResult)
+	stream.pump(func() interface{} { return new(PingResult) }, func(v interface{}, err error) bool {
+		res := *v.(*PingResult)
+		if err != nil {
+			res = PingResult{Text: err.Error()}
+		}
+		select {
+		case out <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func(bool) { close(out) })
+	return out, nil
+}
+
+// parseMultiaddrs - parses the textual form of multiaddrs.
+func parseMultiaddrs(addrs []string) ([]ma.Multiaddr, error) {
+	out := make([]ma.Multiaddr, 0, len(addrs))
+	for _, s := range addrs {
+		a, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
@@ -0,0 +1,54 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSwarmConnectionsAndPing(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("swarm/peers", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("latency") != "true" {
+			t.Errorf("latency option not set")
+		}
+		fmt.Fprintf(w, `{"Peers":[{"Addr":"/ip4/1.2.3.4/tcp/4001","Peer":"%s","Latency":"1.5ms","Muxer":"yamux","Streams":[{"Protocol":"/mefs/1.0"}]},`+
+			`{"Addr":"/ip4/5.6.7.8/tcp/4001","Peer":"%s","Latency":"n/a"}]}`, testPeerID, testPeerID)
+	})
+	n.Reply("ping", `{"Success":true,"Time":0,"Text":"PING"}`+"\n"+
+		`{"Success":true,"Time":2000000,"Text":""}`+"\n"+
+		`{"Success":true,"Time":3000000,"Text":""}`+"\n")
+
+	c := n.Client()
+
+	conns, err := c.SwarmConnections(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conns) != 2 {
+		t.Fatalf("expected 2 connections, got %d", len(conns))
+	}
+	if conns[0].Latency != 1500*time.Microsecond || conns[0].Addr.String() != "/ip4/1.2.3.4/tcp/4001" || conns[0].Peer.Pretty() != testPeerID {
+		t.Errorf("unexpected connection %+v", conns[0])
+	}
+	if conns[1].Latency != 0 {
+		t.Errorf("expected unknown latency, got %v", conns[1].Latency)
+	}
+
+	results, err := c.Ping(context.Background(), testPeerID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rtts []time.Duration
+	for res := range results {
+		if res.Time > 0 {
+			rtts = append(rtts, res.Time)
+		}
+	}
+	if len(rtts) != 2 || rtts[0] != 2*time.Millisecond {
+		t.Errorf("unexpected round-trip times %v", rtts)
+	}
+}
@@ -44,7 +44,6 @@ import (
 	"strings"
 	"time"
 
-	files "github.com/ipfs/go-ipfs-files"
 	homedir "github.com/mitchellh/go-homedir"
 	ma "github.com/multiformats/go-multiaddr"
 	manet "github.com/multiformats/go-multiaddr-net"
@@ -95,17 +94,135 @@ type Client struct {
 	// lookup indicates type of url lookup supported by server. If not specified,
 	// default to Auto.
 	lookup BucketLookupType
+
+	// Default redundancy for new buckets.
+	bucketOpts BucketOptions
+
+	// Retry policy of node commands.
+	retry RetryPolicy
+
+	// Deadline of the node commands decoded by Exec, see
+	// Options.Timeout.
+	commandTimeout time.Duration
+
+	// Client logger, never nil.
+	logger Logger
+
+	// Interceptors wrapping every node command.
+	interceptors []Interceptor
+
+	// Verify the blocks against their keys.
+	verifyBlocks bool
+
+	// User of the lfs commands, set by WithIdentity.
+	identity *Identity
+
+	// Command trees of the strict mode, nil when it is off.
+	commands *commandCache
 }
 
 // Options for New method
 type Options struct {
-	Creds        *credentials.Credentials
-	Secure       bool
-	Region       string
+	Creds  *credentials.Credentials
+	Secure bool
+
+	// Address and Password identify the MEFS user, they are used to
+	// build static credentials when Creds is not set.
+	Address  string
+	Password string
+
+	// RepoPath is the MEFS repo directory, defaults to $MEFS_PATH
+	// or ~/.mefs when empty.
+	RepoPath string
+
+	// UseAPIFile reads the node endpoint from the api file inside
+	// RepoPath when no endpoint is passed to NewWithOptions.
+	UseAPIFile bool
+
+	// BucketOptions is the default redundancy used by MakeBucket.
+	BucketOptions BucketOptions
+
+	// Timeout of a single node command, zero means no timeout. It
+	// bounds the commands returning one response; uploads and
+	// streamed outputs, such as LogTail, Ping or object downloads,
+	// are only bounded by the context of the call.
+	Timeout time.Duration
+
+	// Transport overrides the default http transport.
+	Transport http.RoundTripper
+
+	// Retry controls how node commands are retried.
+	Retry RetryPolicy
+
+	// Logger receives the client logs, defaults to a no-op logger.
+	Logger Logger
+
+	// Interceptors wrap every node command, see Client.Use.
+	Interceptors []Interceptor
+
+	// Metrics records per-command statistics, see MetricsInterceptor.
+	Metrics Metrics
+
+	// VerifyBlocks checks fetched and stored blocks against their
+	// keys, see SetBlockVerification.
+	VerifyBlocks bool
+
+	// StrictCommands checks every request against the command tree
+	// of the node, see SetStrictCommands.
+	StrictCommands bool
+
+	// Deprecated: Region is S3 specific and ignored by MEFS nodes,
+	// set UseAPIFile instead of passing Region "local".
+	Region string
+
+	// Deprecated: BucketLookup is S3 specific and ignored by MEFS nodes.
 	BucketLookup BucketLookupType
 	// Add future fields here
 }
 
+// BucketOptions - redundancy parameters of a bucket, zero values
+// leave the choice to the node.
+type BucketOptions struct {
+	Policy      int
+	DataCount   int
+	ParityCount int
+}
+
+// lfsOpts returns the non-zero bucket options as LfsOpts.
+func (o BucketOptions) lfsOpts() []LfsOpts {
+	var opts []LfsOpts
+	if o.Policy != 0 {
+		opts = append(opts, SetPolicy(o.Policy))
+	}
+	if o.DataCount != 0 {
+		opts = append(opts, SetDataCount(o.DataCount))
+	}
+	if o.ParityCount != 0 {
+		opts = append(opts, SetParityCount(o.ParityCount))
+	}
+	return opts
+}
+
+// validate - checks that the options are consistent.
+func (opts Options) validate() error {
+	if opts.Creds != nil && opts.Address != "" {
+		return ErrInvalidArgument("Creds and Address cannot be set together.")
+	}
+	if opts.Address != "" && !isValidAddress(opts.Address) {
+		return ErrInvalidArgument(fmt.Sprintf("Address %s is not a valid MEFS address.", opts.Address))
+	}
+	if opts.BucketOptions.Policy < 0 || opts.BucketOptions.DataCount < 0 || opts.BucketOptions.ParityCount < 0 {
+		return ErrInvalidArgument("BucketOptions cannot be negative.")
+	}
+	if opts.Timeout < 0 {
+		return ErrInvalidArgument("Timeout cannot be negative.")
+	}
+	if opts.Retry.MaxRetries < 0 || opts.Retry.Unit < 0 || opts.Retry.Cap < 0 {
+		return ErrInvalidArgument("Retry cannot be negative.")
+	}
+	return nil
+}
+
 // Global constants.
 const (
 	libraryName    = "minio-go"
@@ -180,9 +297,53 @@ func NewWithRegion(endpoint, accessKeyID, secretAccessKey string, secure bool, r
 	return privateNew(endpoint, creds, secure, region, BucketLookupAuto)
 }
 
-// NewWithOptions - instantiate minio client with options
+// NewWithOptions - instantiate mefs client with options. The endpoint
+// may be empty when opts.UseAPIFile is set, it is then read from the
+// api file of the MEFS repo.
 func NewWithOptions(endpoint string, opts *Options) (*Client, error) {
-	return privateNew(endpoint, opts.Creds, opts.Secure, opts.Region, opts.BucketLookup)
+	if opts == nil {
+		return nil, ErrInvalidArgument("Options cannot be empty.")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	if endpoint == "" && (opts.UseAPIFile || opts.Region == "local") {
+		api, err := readAPIFile(opts.RepoPath)
+		if err != nil {
+			return nil, err
+		}
+		endpoint = api
+	}
+	if endpoint == "" {
+		return nil, ErrInvalidArgument("Endpoint cannot be empty unless UseAPIFile is set.")
+	}
+
+	creds := opts.Creds
+	if creds == nil {
+		creds = credentials.NewStaticV4(opts.Address, opts.Password, "")
+	}
+
+	clnt, err := privateNew(endpoint, creds, opts.Secure, "", opts.BucketLookup)
+	if err != nil {
+		return nil, err
+	}
+	clnt.bucketOpts = opts.BucketOptions
+	clnt.verifyBlocks = opts.VerifyBlocks
+	clnt.SetStrictCommands(opts.StrictCommands)
+	clnt.retry = opts.Retry
+	clnt.commandTimeout = opts.Timeout
+	if opts.Transport != nil {
+		clnt.SetCustomTransport(opts.Transport)
+	}
+	if opts.Logger != nil {
+		clnt.logger = opts.Logger
+	}
+	if opts.Metrics != nil {
+		clnt.Use(MetricsInterceptor(opts.Metrics))
+	}
+	clnt.Use(opts.Interceptors...)
+	return clnt, nil
 }
 
 // lockedRandSource provides protected rand source, implements rand.Source interface.
@@ -271,30 +432,51 @@ const (
 	EnvDir          = "MEFS_PATH"
 )
 
-func privateNew(endpoint string, creds *credentials.Credentials, secure bool, region string, lookup BucketLookupType) (*Client, error) {
-	if region == "local" {
-		baseDir := os.Getenv(EnvDir)
-		if baseDir == "" {
-			baseDir = DefaultPathRoot
-		}
+// readAPIFile - returns the node endpoint stored in the api file of
+// the MEFS repo, repoPath defaults to $MEFS_PATH or ~/.mefs.
+func readAPIFile(repoPath string) (string, error) {
+	baseDir := repoPath
+	if baseDir == "" {
+		baseDir = os.Getenv(EnvDir)
+	}
+	if baseDir == "" {
+		baseDir = DefaultPathRoot
+	}
 
-		baseDir, err := homedir.Expand(baseDir)
-		if err != nil {
-			return nil, err
-		}
+	baseDir, err := homedir.Expand(baseDir)
+	if err != nil {
+		return "", err
+	}
 
-		apiFile := path.Join(baseDir, DefaultApiFile)
+	api, err := ioutil.ReadFile(path.Join(baseDir, DefaultApiFile))
+	if err != nil {
+		return "", err
+	}
 
-		if _, err := os.Stat(apiFile); err != nil {
-			return nil, err
+	return strings.TrimSpace(string(api)), nil
+}
+
+// isValidAddress - checks a MEFS user address, 0x followed by 40 hex digits.
+func isValidAddress(addr string) bool {
+	if len(addr) != 42 || !strings.HasPrefix(addr, "0x") {
+		return false
+	}
+	for _, r := range addr[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
 		}
+	}
+	return true
+}
 
-		api, err := ioutil.ReadFile(apiFile)
+func privateNew(endpoint string, creds *credentials.Credentials, secure bool, region string, lookup BucketLookupType) (*Client, error) {
+	// Region "local" is kept for compatibility, prefer Options.UseAPIFile.
+	if region == "local" {
+		api, err := readAPIFile("")
 		if err != nil {
 			return nil, err
 		}
-
-		endpoint = strings.TrimSpace(string(api))
+		endpoint = api
 	}
 
 	// instantiate new Client.
@@ -329,6 +511,12 @@ func privateNew(endpoint string, creds *credentials.Credentials, secure bool, re
 		return fmt.Errorf("unexpected redirect")
 	}
 
+	// Instantiate a new lock-protected random seed for retries.
+	clnt.random = rand.New(&lockedRandSource{src: rand.NewSource(time.Now().UTC().UnixNano())})
+
+	clnt.lookup = lookup
+	clnt.logger = nopLogger{}
+
 	return clnt, nil
 }
 
@@ -362,7 +550,8 @@ func (c *Client) SetCustomTransport(customHTTPTransport http.RoundTripper) {
 	}
 }
 
-// TraceOn - enable HTTP tracing.
+// TraceOn - enable HTTP tracing. Node commands are traced as well,
+// with secrets redacted and large bodies elided.
 func (c *Client) TraceOn(outputStream io.Writer) {
 	// if outputStream is nil then default to os.Stdout.
 	if outputStream == nil {
@@ -756,10 +945,12 @@ func (c *Client) ID(peer ...string) (*IdOutput, error) {
 	return &out, nil
 }
 
+// Pin types, AllPins matches all of them in PinLs.
 const (
 	DirectPin    = "direct"
 	RecursivePin = "recursive"
 	IndirectPin  = "indirect"
+	AllPins      = "all"
 )
 
 type PeerInfo struct {
@@ -767,16 +958,25 @@ type PeerInfo struct {
 	ID    string
 }
 
+// FindPeer - returns the addresses of peer, from the FinalPeer event
+// of the lookup, see FindPeerStream for all the events.
 func (c *Client) FindPeer(peer string) (*PeerInfo, error) {
-	var peers struct{ Responses []PeerInfo }
-	err := c.Request("dht/findpeer", peer).Exec(context.Background(), &peers)
+	events, err := c.FindPeerStream(context.Background(), peer)
 	if err != nil {
 		return nil, err
 	}
-	if len(peers.Responses) == 0 {
+	final, err := lastQueryEvent(events)
+	if err != nil {
+		return nil, err
+	}
+	if len(final.Responses) == 0 {
 		return nil, errors.New("peer not found")
 	}
-	return &peers.Responses[0], nil
+	info := &PeerInfo{ID: final.Responses[0].ID.Pretty()}
+	for _, addr := range final.Responses[0].Addrs {
+		info.Addrs = append(info.Addrs, addr.String())
+	}
+	return info, nil
 }
 
 func (c *Client) ResolvePath(path string) (string, error) {
@@ -794,12 +994,16 @@ func (c *Client) ResolvePath(path string) (string, error) {
 
 // returns ipfs version and commit sha
 func (c *Client) Version() (string, string, error) {
+	return c.versionContext(context.Background())
+}
+
+func (c *Client) versionContext(ctx context.Context) (string, string, error) {
 	ver := struct {
 		Version string
 		Commit  string
 	}{}
 
-	if err := c.Request("version").Exec(context.Background(), &ver); err != nil {
+	if err := c.Request("version").Exec(ctx, &ver); err != nil {
 		return "", "", err
 	}
 	return ver.Version, ver.Commit, nil
@@ -823,34 +1027,17 @@ func (c *Client) BlockStat(path string) (string, int, error) {
 }
 
 func (c *Client) BlockGet(path string) ([]byte, error) {
-	resp, err := c.Request("block/get", path).Send(context.Background())
+	rc, err := c.BlockGetReader(context.Background(), path)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Close()
+	defer rc.Close()
 
-	if resp.Error != nil {
-		return nil, resp.Error
-	}
-
-	return ioutil.ReadAll(resp.Output)
+	return ioutil.ReadAll(rc)
 }
 
 func (c *Client) BlockPut(block []byte, format, mhtype string, mhlen int) (string, error) {
-	var out struct {
-		Key string
-	}
-
-	fr := files.NewBytesFile(block)
-	slf := files.NewSliceDirectory([]files.DirEntry{files.FileEntry("", fr)})
-	fileReader := files.NewMultiFileReader(slf, true)
-
-	return out.Key, c.Request("block/put").
-		Option("mhtype", mhtype).
-		Option("format", format).
-		Option("mhlen", mhlen).
-		Body(fileReader).
-		Exec(context.Background(), &out)
+	return c.BlockPutReader(context.Background(), bytes.NewReader(block), format, mhtype, mhlen)
 }
 
 type SwarmStreamInfo struct {
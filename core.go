@@ -47,7 +47,7 @@ func NewCore(endpoint string, accessKeyID, secretAccessKey string, secure bool)
 // ListObjects - List all the objects at a prefix, optionally with marker and delimiter
 // you can further filter the results.
 func (c Core) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int) (result ListBucketResult, err error) {
-	return c.listObjectsQuery(bucket, prefix, marker, delimiter, maxKeys)
+	return c.listObjectsQuery(context.Background(), bucket, prefix, marker, delimiter, maxKeys)
 }
 
 // ListObjectsV2 - Lists all the objects at a prefix, similar to ListObjects() but uses
@@ -4,11 +4,14 @@ go 1.12
 
 require (
 	github.com/dustin/go-humanize v1.0.0 // indirect
+	github.com/ipfs/go-cid v0.0.7
 	github.com/ipfs/go-ipfs-files v0.0.6
 	github.com/minio/sha256-simd v0.1.1
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/multiformats/go-multiaddr v0.1.1
 	github.com/multiformats/go-multiaddr-net v0.1.1
+	github.com/multiformats/go-multihash v0.0.14
+	github.com/multiformats/go-varint v0.0.6 // indirect
 	github.com/sirupsen/logrus v1.4.2 // indirect
 	github.com/smartystreets/goconvey v0.0.0-20190330032615-68dc04aab96a // indirect
 	golang.org/x/crypto v0.0.0-20190611184440-5c40567a22f8
@@ -0,0 +1,79 @@
+package mefs
+
+import "context"
+
+// Handler sends a node command request and returns its response.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor wraps every node command sent by a Client. The command
+// name, arguments, options and headers are available on req and can
+// be modified before calling next; an interceptor may also return
+// without calling next, e.g. to enforce a quota.
+//
+//   c.Use(func(ctx context.Context, req *mefs.Request, next mefs.Handler) (*mefs.Response, error) {
+//           req.Headers["Authorization"] = "Bearer " + token
+//           return next(ctx, req)
+//   })
+type Interceptor func(ctx context.Context, req *Request, next Handler) (*Response, error)
+
+// Use - appends interceptors to the client chain. The first
+// interceptor added is the outermost one, all of them run before
+// the retries of the client retry policy.
+func (c *Client) Use(interceptors ...Interceptor) {
+	for _, ic := range interceptors {
+		if ic != nil {
+			c.interceptors = append(c.interceptors, ic)
+		}
+	}
+}
+
+// commandHandler - returns the handler chain of node commands.
+func (c *Client) commandHandler() Handler {
+	h := chainInterceptor(c.retryCommand, c.sendCommand)
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		h = chainInterceptor(c.interceptors[i], h)
+	}
+	if c.commands != nil {
+		h = chainInterceptor(c.validateCommand, h)
+	}
+	return h
+}
+
+// chainInterceptor - returns a handler running ic with next.
+func chainInterceptor(ic Interceptor, next Handler) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		return ic(ctx, req, next)
+	}
+}
+
+// sendCommand - the last handler of the chain, sends req to the node.
+func (c *Client) sendCommand(ctx context.Context, req *Request) (*Response, error) {
+	req.Ctx = ctx
+	return req.Send(c.commandHTTPClient())
+}
+
+// retryCommand - retries the requests without body which failed
+// before being sent according to the client retry policy.
+func (c *Client) retryCommand(ctx context.Context, req *Request, next Handler) (*Response, error) {
+	reqRetry := c.retry.MaxRetries + 1
+	if req.Body != nil {
+		reqRetry = 1
+	}
+
+	// Create a done channel to control 'newRetryTimer' go routine.
+	doneCh := make(chan struct{}, 1)
+
+	// Indicate to our routine to exit cleanly upon return.
+	defer close(doneCh)
+
+	var resp *Response
+	var err error
+	for range c.newRetryTimer(reqRetry, c.retry.unit(), c.retry.cap(), MaxJitter, doneCh) {
+		resp, err = next(ctx, req)
+		if err != nil && ctx.Err() == nil && isRequestNotSent(err) {
+			continue // Retry.
+		}
+		break
+	}
+	return resp, err
+}
@@ -0,0 +1,90 @@
+package mefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInterceptorChain(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("lfs/list_objects", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%q", r.Header.Get("X-Token"))
+	})
+
+	c := n.Client()
+
+	var calls []string
+	c.Use(func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		calls = append(calls, "outer:"+req.Command+":"+req.Opts["address"])
+		return next(ctx, req)
+	}, func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		calls = append(calls, "inner:"+req.Args[0])
+		req.Headers["X-Token"] = "secret"
+		return next(ctx, req)
+	})
+
+	var token string
+	if err := c.Request("lfs/list_objects", "b1").Option("address", "0x01").Exec(context.Background(), &token); err != nil {
+		t.Fatal(err)
+	}
+	if token != "secret" {
+		t.Errorf("expected injected header, got %q", token)
+	}
+	if want := []string{"outer:lfs/list_objects:0x01", "inner:b1"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+
+	errQuota := errors.New("quota exceeded")
+	c.Use(func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		return nil, errQuota
+	})
+	if err := c.Request("lfs/put_object", "b1").Exec(context.Background(), nil); err != errQuota {
+		t.Errorf("expected %v, got %v", errQuota, err)
+	}
+}
+
+// failingTransport fails every request with err, counting them.
+type failingTransport struct {
+	err   error
+	calls int
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return nil, t.err
+}
+
+func TestRetryCommand(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+
+	c := n.Client()
+	c.retry = RetryPolicy{MaxRetries: 2, Unit: time.Millisecond}
+
+	// a request which did not reach the node is retried.
+	tr := &failingTransport{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	c.SetCustomTransport(tr)
+	if err := c.Request("lfs/create_bucket", "bucket01").Exec(context.Background(), nil); err == nil {
+		t.Fatal("expected a dial error")
+	}
+	if tr.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", tr.calls)
+	}
+
+	// a request which may have been run by the node is not.
+	tr = &failingTransport{err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}
+	c.SetCustomTransport(tr)
+	if err := c.Request("lfs/create_bucket", "bucket01").Exec(context.Background(), nil); err == nil {
+		t.Fatal("expected a read error")
+	}
+	if tr.calls != 1 {
+		t.Errorf("expected a single attempt, got %d", tr.calls)
+	}
+}
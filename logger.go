@@ -0,0 +1,98 @@
+package mefs
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+// Log levels, in increasing severity.
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String returns the lower-case name of the level.
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Field is a structured key/value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger receives the logs of a Client. Implementations must be
+// safe for concurrent use.
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...Field)
+}
+
+// nopLogger discards everything, it is the default Client logger.
+type nopLogger struct{}
+
+func (nopLogger) Log(LogLevel, string, ...Field) {}
+
+// stdLogger writes logfmt-like lines through a standard library logger.
+type stdLogger struct {
+	min LogLevel
+	l   *log.Logger
+}
+
+// NewStdLogger returns a Logger writing entries of at least level min
+// to w, one line per entry, e.g.
+//
+//   level=error msg="put object failed" bucket=b1 err="..."
+func NewStdLogger(w io.Writer, min LogLevel) Logger {
+	return &stdLogger{min: min, l: log.New(w, "", log.LstdFlags)}
+}
+
+func (s *stdLogger) Log(level LogLevel, msg string, fields ...Field) {
+	if level < s.min {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%q", level, msg)
+	for _, f := range fields {
+		v := fmt.Sprint(f.Value)
+		if strings.ContainsAny(v, " \t\"=") || v == "" {
+			v = strconv.Quote(v)
+		}
+		fmt.Fprintf(&b, " %s=%s", f.Key, v)
+	}
+	s.l.Print(b.String())
+}
+
+// SetLogger - set the client logger, nil restores the no-op logger.
+func (c *Client) SetLogger(l Logger) {
+	if l == nil {
+		l = nopLogger{}
+	}
+	c.logger = l
+}
+
+// getLogger - returns the client logger, never nil.
+func (c Client) getLogger() Logger {
+	if c.logger == nil {
+		return nopLogger{}
+	}
+	return c.logger
+}
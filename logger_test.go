@@ -0,0 +1,35 @@
+package mefs
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(&buf, LogInfo)
+
+	l.Log(LogDebug, "hidden", Field{"k", "v"})
+	if buf.Len() != 0 {
+		t.Fatalf("debug entry should be filtered, got %q", buf.String())
+	}
+
+	l.Log(LogError, "put object failed", Field{"bucket", "b1"}, Field{"err", errors.New("no space")})
+	out := buf.String()
+	for _, want := range []string{`level=error`, `msg="put object failed"`, `bucket=b1`, `err="no space"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestClientDefaultLogger(t *testing.T) {
+	var c Client
+	c.getLogger().Log(LogError, "must not panic")
+	c.SetLogger(nil)
+	if _, ok := c.getLogger().(nopLogger); !ok {
+		t.Fatal("expected no-op logger")
+	}
+}
@@ -0,0 +1,84 @@
+package mefs
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// prometheusNamespace prefixes the exposed metric names.
+const prometheusNamespace = "mefs_"
+
+// PrometheusHandler - returns an http.Handler exposing the snapshot
+// of m in the Prometheus text format.
+func PrometheusHandler(m *MemoryMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		writePrometheus(bw, m.Snapshot())
+		bw.Flush()
+	})
+}
+
+// writePrometheus - writes s in the Prometheus text format.
+func writePrometheus(w *bufio.Writer, s MetricsSnapshot) {
+	counters := make([]string, 0, len(s.Counters))
+	for name := range s.Counters {
+		counters = append(counters, name)
+	}
+	sort.Strings(counters)
+	for _, name := range counters {
+		byCmd := s.Counters[name]
+		cmds := make([]string, 0, len(byCmd))
+		for cmd := range byCmd {
+			cmds = append(cmds, cmd)
+		}
+		sort.Strings(cmds)
+
+		fmt.Fprintf(w, "# TYPE %s%s counter\n", prometheusNamespace, name)
+		for _, cmd := range cmds {
+			fmt.Fprintf(w, "%s%s{command=%s} %s\n", prometheusNamespace, name, quoteLabel(cmd), formatFloat(byCmd[cmd]))
+		}
+	}
+
+	names := make([]string, 0, len(s.Histograms))
+	for name := range s.Histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		byCmd := s.Histograms[name]
+		cmds := make([]string, 0, len(byCmd))
+		for cmd := range byCmd {
+			cmds = append(cmds, cmd)
+		}
+		sort.Strings(cmds)
+
+		fmt.Fprintf(w, "# TYPE %s%s histogram\n", prometheusNamespace, name)
+		for _, cmd := range cmds {
+			h := byCmd[cmd]
+			label := quoteLabel(cmd)
+			for i, le := range h.Buckets {
+				fmt.Fprintf(w, "%s%s_bucket{command=%s,le=\"%s\"} %d\n", prometheusNamespace, name, label, formatFloat(le), h.Counts[i])
+			}
+			fmt.Fprintf(w, "%s%s_bucket{command=%s,le=\"+Inf\"} %d\n", prometheusNamespace, name, label, h.Count)
+			fmt.Fprintf(w, "%s%s_sum{command=%s} %s\n", prometheusNamespace, name, label, formatFloat(h.Sum))
+			fmt.Fprintf(w, "%s%s_count{command=%s} %d\n", prometheusNamespace, name, label, h.Count)
+		}
+	}
+}
+
+// quoteLabel - quotes a label value as required by the text format.
+func quoteLabel(v string) string {
+	v = strings.Replace(v, `\`, `\\`, -1)
+	v = strings.Replace(v, `"`, `\"`, -1)
+	v = strings.Replace(v, "\n", `\n`, -1)
+	return `"` + v + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,243 @@
+package mefs
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Names of the metrics recorded by MetricsInterceptor, all of them
+// are keyed by command, e.g. "lfs/put_object".
+const (
+	MetricRequests      = "requests_total"
+	MetricErrors        = "errors_total"
+	MetricBytesSent     = "bytes_sent_total"
+	MetricBytesReceived = "bytes_received_total"
+	MetricDuration      = "request_duration_seconds"
+)
+
+// DefaultDurationBuckets - upper bounds, in seconds, of the command
+// duration histogram.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// Metrics collects per-command counters and histograms.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	AddCounter(name, command string, delta float64)
+	ObserveHistogram(name, command string, value float64)
+}
+
+// MetricsInterceptor - returns an interceptor recording requests,
+// errors, bytes moved and duration of every node command into m. The
+// duration and received bytes are recorded once the response output
+// is fully read or closed.
+func MetricsInterceptor(m Metrics) Interceptor {
+	return func(ctx context.Context, req *Request, next Handler) (*Response, error) {
+		start := time.Now()
+		m.AddCounter(MetricRequests, req.Command, 1)
+
+		var sent *countingReader
+		if req.Body != nil {
+			sent = &countingReader{Reader: req.Body}
+			req.Body = sent
+			// keep the boundary of uploads, Send sets the multipart
+			// Content-Type from it.
+			if mb, ok := sent.Reader.(multipartBody); ok {
+				req.Body = &countingMultipart{countingReader: sent, boundary: mb.Boundary()}
+			}
+		}
+		done := func(failed bool, received int64) {
+			if failed {
+				m.AddCounter(MetricErrors, req.Command, 1)
+			}
+			if sent != nil {
+				m.AddCounter(MetricBytesSent, req.Command, float64(atomic.LoadInt64(&sent.n)))
+			}
+			m.AddCounter(MetricBytesReceived, req.Command, float64(received))
+			m.ObserveHistogram(MetricDuration, req.Command, time.Since(start).Seconds())
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil || resp.Error != nil || resp.Output == nil {
+			done(err != nil || (resp != nil && resp.Error != nil), 0)
+			return resp, err
+		}
+		resp.Output = &metricsReader{ReadCloser: resp.Output, done: done}
+		return resp, nil
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
+
+// countingMultipart counts the bytes of a multipart upload.
+type countingMultipart struct {
+	*countingReader
+	boundary string
+}
+
+func (r *countingMultipart) Boundary() string {
+	return r.boundary
+}
+
+// metricsReader reports the response output to MetricsInterceptor
+// on EOF, read error or close, whichever comes first.
+type metricsReader struct {
+	io.ReadCloser
+	n    int64
+	once sync.Once
+	done func(failed bool, received int64)
+}
+
+func (r *metricsReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	if err != nil {
+		r.finish(err != io.EOF)
+	}
+	return n, err
+}
+
+func (r *metricsReader) Close() error {
+	r.finish(false)
+	return r.ReadCloser.Close()
+}
+
+func (r *metricsReader) finish(failed bool) {
+	r.once.Do(func() { r.done(failed, r.n) })
+}
+
+// HistogramSnapshot - state of a histogram, Counts are cumulative
+// and match Buckets, the last implicit bucket being +Inf (Count).
+type HistogramSnapshot struct {
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+// MetricsSnapshot - point in time copy of a MemoryMetrics, indexed
+// by metric name then command.
+type MetricsSnapshot struct {
+	Counters   map[string]map[string]float64
+	Histograms map[string]map[string]HistogramSnapshot
+}
+
+// Commands - returns the sorted commands present in the snapshot.
+func (s MetricsSnapshot) Commands() []string {
+	set := make(map[string]struct{})
+	for _, byCmd := range s.Counters {
+		for cmd := range byCmd {
+			set[cmd] = struct{}{}
+		}
+	}
+	for _, byCmd := range s.Histograms {
+		for cmd := range byCmd {
+			set[cmd] = struct{}{}
+		}
+	}
+	cmds := make([]string, 0, len(set))
+	for cmd := range set {
+		cmds = append(cmds, cmd)
+	}
+	sort.Strings(cmds)
+	return cmds
+}
+
+type metricKey struct {
+	name, command string
+}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// MemoryMetrics is the default in-memory Metrics implementation.
+type MemoryMetrics struct {
+	mutex      sync.Mutex
+	buckets    []float64
+	counters   map[metricKey]float64
+	histograms map[metricKey]*histogram
+}
+
+// NewMemoryMetrics - returns an empty MemoryMetrics, histograms use
+// the given upper bounds or DefaultDurationBuckets when none.
+func NewMemoryMetrics(buckets ...float64) *MemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &MemoryMetrics{
+		buckets:    buckets,
+		counters:   make(map[metricKey]float64),
+		histograms: make(map[metricKey]*histogram),
+	}
+}
+
+// AddCounter - adds delta to the counter name of command.
+func (m *MemoryMetrics) AddCounter(name, command string, delta float64) {
+	m.mutex.Lock()
+	m.counters[metricKey{name, command}] += delta
+	m.mutex.Unlock()
+}
+
+// ObserveHistogram - records value in the histogram name of command.
+func (m *MemoryMetrics) ObserveHistogram(name, command string, value float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	h, ok := m.histograms[metricKey{name, command}]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.histograms[metricKey{name, command}] = h
+	}
+	for i, le := range m.buckets {
+		if value <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// Snapshot - returns a copy of all the metrics.
+func (m *MemoryMetrics) Snapshot() MetricsSnapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	s := MetricsSnapshot{
+		Counters:   make(map[string]map[string]float64),
+		Histograms: make(map[string]map[string]HistogramSnapshot),
+	}
+	for k, v := range m.counters {
+		if s.Counters[k.name] == nil {
+			s.Counters[k.name] = make(map[string]float64)
+		}
+		s.Counters[k.name][k.command] = v
+	}
+	for k, h := range m.histograms {
+		if s.Histograms[k.name] == nil {
+			s.Histograms[k.name] = make(map[string]HistogramSnapshot)
+		}
+		s.Histograms[k.name][k.command] = HistogramSnapshot{
+			Buckets: m.buckets,
+			Counts:  append([]uint64(nil), h.counts...),
+			Count:   h.count,
+			Sum:     h.sum,
+		}
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsInterceptor(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("lfs/get_object", func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		fmt.Fprint(w, "0123456789")
+	})
+	n.Handle("lfs/head_object", func(w http.ResponseWriter, r *http.Request) {
+		nodeError(w, "no such object")
+	})
+
+	m := NewMemoryMetrics()
+	c, err := NewWithOptions(n.Listener.Addr().String(), &Options{Metrics: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Request("lfs/get_object", "b1", "o1").BodyString("hello").Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = resp.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Request("lfs/head_object", "b1", "o1").Exec(context.Background(), nil); err == nil {
+		t.Fatal("expected error")
+	}
+
+	s := m.Snapshot()
+	if v := s.Counters[MetricRequests]["lfs/get_object"]; v != 1 {
+		t.Errorf("expected 1 get_object request, got %v", v)
+	}
+	if v := s.Counters[MetricBytesReceived]["lfs/get_object"]; v != 10 {
+		t.Errorf("expected 10 bytes received, got %v", v)
+	}
+	if v := s.Counters[MetricBytesSent]["lfs/get_object"]; v != 5 {
+		t.Errorf("expected 5 bytes sent, got %v", v)
+	}
+	if v := s.Counters[MetricErrors]["lfs/head_object"]; v != 1 {
+		t.Errorf("expected 1 head_object error, got %v", v)
+	}
+	if h := s.Histograms[MetricDuration]["lfs/get_object"]; h.Count != 1 {
+		t.Errorf("expected 1 duration sample, got %d", h.Count)
+	}
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler(m).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE mefs_requests_total counter",
+		`mefs_requests_total{command="lfs/get_object"} 1`,
+		`mefs_errors_total{command="lfs/head_object"} 1`,
+		"# TYPE mefs_request_duration_seconds histogram",
+		`mefs_request_duration_seconds_bucket{command="lfs/get_object",le="+Inf"} 1`,
+		`mefs_request_duration_seconds_count{command="lfs/head_object"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsUpload(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("block/put", func(w http.ResponseWriter, r *http.Request) {
+		data := readUpload(t, r)
+		fmt.Fprintf(w, `{"Key":"key-%s","Size":%d}`, data, len(data))
+	})
+
+	m := NewMemoryMetrics()
+	c, err := NewWithOptions(n.Listener.Addr().String(), &Options{Metrics: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := c.BlockPut([]byte("hello"), "v0", "sha2-256", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "key-hello" {
+		t.Errorf("expected the uploaded block, got %q", key)
+	}
+	// the multipart framing is counted with the block.
+	if v := m.Snapshot().Counters[MetricBytesSent]["block/put"]; v <= 5 {
+		t.Errorf("expected the upload bytes sent, got %v", v)
+	}
+}
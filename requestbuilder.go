@@ -3,6 +3,7 @@ package mefs
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -76,13 +77,26 @@ func (r *RequestBuilder) Header(name, value string) *RequestBuilder {
 func (r *RequestBuilder) Send(ctx context.Context) (*Response, error) {
 	req := NewRequest(ctx, r.client.url, r.command, r.args...)
 	req.Opts = r.opts
-	req.Headers = r.headers
+	if req.Opts == nil {
+		req.Opts = make(map[string]string)
+	}
+	if r.headers != nil {
+		req.Headers = r.headers
+	}
 	req.Body = r.body
-	return req.Send(r.client.httpClient)
+	req.Logger = r.client.getLogger()
+	return r.client.commandHandler()(ctx, req)
 }
 
 // Exec sends the request a request and decodes the response.
 func (r *RequestBuilder) Exec(ctx context.Context, res interface{}) error {
+	// the response is read before returning, the client timeout
+	// bounds the whole command unless it uploads files.
+	if _, upload := r.body.(multipartBody); r.client.commandTimeout > 0 && !upload {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.client.commandTimeout)
+		defer cancel()
+	}
 	httpRes, err := r.Send(ctx)
 	if err != nil {
 		return err
@@ -98,3 +112,60 @@ func (r *RequestBuilder) Exec(ctx context.Context, res interface{}) error {
 
 	return httpRes.Decode(res)
 }
+
+// Stream sends the request and returns an iterator over the JSON
+// values of the response, for commands emitting a stream of values.
+// The stream must be closed, cancelling ctx closes it as well.
+func (r *RequestBuilder) Stream(ctx context.Context) (*ResponseStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	httpRes, err := r.Send(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if httpRes.Error != nil {
+		cancel()
+		return nil, httpRes.Error
+	}
+	return &ResponseStream{
+		resp:   httpRes,
+		dec:    json.NewDecoder(httpRes.Output),
+		cancel: cancel,
+	}, nil
+}
+
+// ExecStream sends the request and decodes the response values, each
+// into a value allocated by newValue, on the returned channel. The
+// channel is closed at the end of the stream or when ctx is
+// cancelled; the error channel then receives the error ending the
+// stream, if any.
+func (r *RequestBuilder) ExecStream(ctx context.Context, newValue func() interface{}) (<-chan interface{}, <-chan error) {
+	out := make(chan interface{})
+	errCh := make(chan error, 1)
+
+	stream, err := r.Stream(ctx)
+	if err != nil {
+		close(out)
+		errCh <- err
+		close(errCh)
+		return out, errCh
+	}
+
+	stream.pump(newValue, func(v interface{}, err error) bool {
+		if err != nil {
+			errCh <- err
+			return false
+		}
+		select {
+		case out <- v:
+			return true
+		case <-ctx.Done():
+			errCh <- ctx.Err()
+			return false
+		}
+	}, func(bool) {
+		close(out)
+		close(errCh)
+	})
+	return out, errCh
+}
@@ -0,0 +1,95 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type streamValue struct {
+	N int
+}
+
+func newStreamNode(t *testing.T) *fakeNode {
+	n := newFakeNode(t)
+	n.Reply("stream/values", "{\"N\":1}\n{\"N\":2}\n{\"N\":3}\n")
+	n.Handle("stream/error", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Stream-Error")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "{\"N\":1}\n{\"N\":2}\n")
+		w.Header().Set("X-Stream-Error", "query failed")
+	})
+	n.Handle("stream/forever", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for i := 0; ; i++ {
+			if _, err := fmt.Fprintf(w, "{\"N\":%d}\n", i); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	})
+	return n
+}
+
+func TestExecStream(t *testing.T) {
+	n := newStreamNode(t)
+	defer n.Close()
+	c := n.Client()
+
+	out, errCh := c.Request("stream/values").ExecStream(context.Background(), func() interface{} { return new(streamValue) })
+	var sum int
+	for v := range out {
+		sum += v.(*streamValue).N
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	if sum != 6 {
+		t.Errorf("expected 3 values summing to 6, got %d", sum)
+	}
+
+	out, errCh = c.Request("stream/error").ExecStream(context.Background(), func() interface{} { return new(streamValue) })
+	var count int
+	for range out {
+		count++
+	}
+	err := <-errCh
+	if e, ok := err.(*Error); !ok || e.Message != "query failed" {
+		t.Errorf("expected stream error, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 values before the error, got %d", count)
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	n := newStreamNode(t)
+	defer n.Close()
+	c := n.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.Request("stream/forever").Stream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	var v streamValue
+	if err = stream.Next(&v); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for err == nil {
+		err = stream.Next(&v)
+	}
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
@@ -3,13 +3,11 @@ package mefs
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
 
 	files "github.com/ipfs/go-ipfs-files"
@@ -23,6 +21,9 @@ type Request struct {
 	Opts    map[string]string
 	Body    io.Reader
 	Headers map[string]string
+
+	// Logger receives the warnings of Send, defaults to no-op.
+	Logger Logger
 }
 
 func NewRequest(ctx context.Context, url, command string, args ...string) *Request {
@@ -45,14 +46,15 @@ func NewRequest(ctx context.Context, url, command string, args ...string) *Reque
 }
 
 type trailerReader struct {
-	resp *http.Response
+	resp    *http.Response
+	command string
 }
 
 func (r *trailerReader) Read(b []byte) (int, error) {
 	n, err := r.resp.Body.Read(b)
 	if err != nil {
 		if e := r.resp.Trailer.Get("X-Stream-Error"); e != "" {
-			err = errors.New(e)
+			err = &Error{Command: r.command, Message: e}
 		}
 	}
 	return n, err
@@ -65,6 +67,8 @@ func (r *trailerReader) Close() error {
 type Response struct {
 	Output io.ReadCloser
 	Error  *Error
+
+	ctx context.Context
 }
 
 func (r *Response) Close() error {
@@ -91,10 +95,88 @@ func (r *Response) Decode(dec interface{}) error {
 	return json.NewDecoder(r.Output).Decode(dec)
 }
 
+// ResponseStream decodes the JSON values of a multi-value command
+// response one at a time.
+type ResponseStream struct {
+	resp   *Response
+	dec    *json.Decoder
+	cancel context.CancelFunc
+}
+
+// Next decodes the next value into v. It returns io.EOF once the
+// stream is over, or the stream error sent by the node in the
+// X-Stream-Error trailer.
+func (s *ResponseStream) Next(v interface{}) error {
+	err := s.dec.Decode(v)
+	if err == io.EOF {
+		return io.EOF
+	}
+	if err != nil && s.resp.ctxErr() != nil {
+		return s.resp.ctxErr()
+	}
+	return err
+}
+
+// Close cancels the request and closes the response without
+// draining it, the stream may be infinite.
+func (s *ResponseStream) Close() error {
+	s.cancel()
+	return s.resp.Output.Close()
+}
+
+// pump - decodes the values of the stream in a goroutine, the loop
+// of the streaming commands. Each value is allocated by newValue and
+// passed to send with the decoding error, if any; the loop stops at
+// the end of the stream, after an error or when send returns false,
+// e.g. once ctx is done. The stream is then closed and done called,
+// with eof true when the stream ended.
+func (s *ResponseStream) pump(newValue func() interface{}, send func(v interface{}, err error) bool, done func(eof bool)) {
+	go func() {
+		eof := false
+		defer func() { done(eof) }()
+		defer s.Close()
+		for {
+			v := newValue()
+			err := s.Next(v)
+			if err == io.EOF {
+				eof = true
+				return
+			}
+			if !send(v, err) || err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func (r *Response) ctxErr() error {
+	if r.ctx == nil {
+		return nil
+	}
+	return r.ctx.Err()
+}
+
+// Error codes of the node, as set by go-ipfs-cmds.
+const (
+	cmdsErrNormal = iota // the command failed
+	cmdsErrClient        // the request is invalid, e.g. a bad argument
+)
+
+// errCommandNotFound is the message of the errors of unknown commands.
+const errCommandNotFound = "command not found"
+
 type Error struct {
 	Command string
 	Message string
 	Code    int
+
+	// StatusCode is the HTTP status of the response, zero when the
+	// error came in the trailer of a stream.
+	StatusCode int `json:"-"`
+
+	// decoded is true when the error was decoded from the JSON error
+	// body of the node, rather than e.g. a proxy error page.
+	decoded bool
 }
 
 func (e *Error) Error() string {
@@ -108,6 +190,15 @@ func (e *Error) Error() string {
 	return out + e.Message
 }
 
+// multipartBody is a request body sent as a multipart form, e.g.
+// files.MultiFileReader or a body wrapping it.
+type multipartBody interface {
+	io.Reader
+	Boundary() string
+}
+
+var _ multipartBody = (*files.MultiFileReader)(nil)
+
 func (r *Request) Send(c *http.Client) (*Response, error) {
 	url := r.getURL()
 	req, err := http.NewRequest("POST", url, r.Body)
@@ -122,7 +213,7 @@ func (r *Request) Send(c *http.Client) (*Response, error) {
 		req.Header.Add(k, v)
 	}
 
-	if fr, ok := r.Body.(*files.MultiFileReader); ok {
+	if fr, ok := r.Body.(multipartBody); ok {
 		req.Header.Set("Content-Type", "multipart/form-data; boundary="+fr.Boundary())
 		req.Header.Set("Content-Disposition", "form-data; name=\"files\"")
 	}
@@ -136,31 +227,34 @@ func (r *Request) Send(c *http.Client) (*Response, error) {
 	parts := strings.Split(contentType, ";")
 	contentType = parts[0]
 
-	nresp := new(Response)
+	nresp := &Response{ctx: r.Ctx}
 
-	nresp.Output = &trailerReader{resp}
+	nresp.Output = &trailerReader{resp: resp, command: r.Command}
 	if resp.StatusCode >= http.StatusBadRequest {
 		e := &Error{
-			Command: r.Command,
+			Command:    r.Command,
+			StatusCode: resp.StatusCode,
 		}
 		switch {
 		case resp.StatusCode == http.StatusNotFound:
-			e.Message = "command not found"
+			e.Message = errCommandNotFound
 		case contentType == "text/plain":
 			out, err := ioutil.ReadAll(resp.Body)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "ipfs-shell: warning! response (%d) read error: %s\n", resp.StatusCode, err)
+				r.logger().Log(LogWarn, "response read error", Field{"command", r.Command}, Field{"status", resp.StatusCode}, Field{"err", err})
 			}
 			e.Message = string(out)
 		case contentType == "application/json":
 			if err = json.NewDecoder(resp.Body).Decode(e); err != nil {
-				fmt.Fprintf(os.Stderr, "ipfs-shell: warning! response (%d) unmarshall error: %s\n", resp.StatusCode, err)
+				r.logger().Log(LogWarn, "response unmarshall error", Field{"command", r.Command}, Field{"status", resp.StatusCode}, Field{"err", err})
+			} else {
+				e.decoded = true
 			}
 		default:
-			fmt.Fprintf(os.Stderr, "ipfs-shell: warning! unhandled response (%d) encoding: %s", resp.StatusCode, contentType)
+			r.logger().Log(LogWarn, "unhandled response encoding", Field{"command", r.Command}, Field{"status", resp.StatusCode}, Field{"contentType", contentType})
 			out, err := ioutil.ReadAll(resp.Body)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "ipfs-shell: response (%d) read error: %s\n", resp.StatusCode, err)
+				r.logger().Log(LogWarn, "response read error", Field{"command", r.Command}, Field{"status", resp.StatusCode}, Field{"err", err})
 			}
 			e.Message = fmt.Sprintf("unknown ipfs-shell error encoding: %q - %q", contentType, out)
 		}
@@ -175,6 +269,13 @@ func (r *Request) Send(c *http.Client) (*Response, error) {
 	return nresp, nil
 }
 
+func (r *Request) logger() Logger {
+	if r.Logger == nil {
+		return nopLogger{}
+	}
+	return r.Logger
+}
+
 func (r *Request) getURL() string {
 
 	values := make(url.Values)
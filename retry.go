@@ -85,6 +85,22 @@ func (c Client) newRetryTimer(maxRetry int, unit time.Duration, cap time.Duratio
 	return attemptCh
 }
 
+// isRequestNotSent - reports whether err happened while connecting to
+// the node, before any byte of the request was sent.
+func isRequestNotSent(err error) bool {
+	e, ok := err.(*url.Error)
+	if !ok {
+		return false
+	}
+	switch ne := e.Err.(type) {
+	case *net.OpError:
+		return ne.Op == "dial"
+	case *net.DNSError:
+		return true
+	}
+	return strings.Contains(e.Err.Error(), "net/http: TLS handshake timeout")
+}
+
 // isHTTPReqErrorRetryable - is http requests error retryable, such
 // as i/o timeout, connection broken etc..
 func isHTTPReqErrorRetryable(err error) bool {
@@ -155,3 +171,34 @@ func isHTTPStatusRetryable(httpStatusCode int) (ok bool) {
 	_, ok = retryableHTTPStatusCodes[httpStatusCode]
 	return ok
 }
+
+// RetryPolicy - retry behaviour of node commands. Requests without
+// a body are retried only when they failed before reaching the node,
+// i.e. on dial, DNS and TLS handshake errors. A request which may have
+// been sent, e.g. one timing out awaiting the response, is never
+// retried as many commands, such as lfs/create_bucket or pubsub/pub,
+// are not safe to run twice.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt,
+	// zero disables retries.
+	MaxRetries int
+
+	// Unit and Cap bound the exponential backoff, they default to
+	// DefaultRetryUnit and DefaultRetryCap.
+	Unit time.Duration
+	Cap  time.Duration
+}
+
+func (p RetryPolicy) unit() time.Duration {
+	if p.Unit == 0 {
+		return DefaultRetryUnit
+	}
+	return p.Unit
+}
+
+func (p RetryPolicy) cap() time.Duration {
+	if p.Cap == 0 {
+		return DefaultRetryCap
+	}
+	return p.Cap
+}
@@ -19,10 +19,17 @@ package mefs
 
 import (
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
+	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync"
+	"testing"
 )
 
 // Contains common used utilities for tests.
@@ -73,3 +80,101 @@ func mustParseBool(str string) bool {
 	}
 	return b
 }
+
+// fakeNode is a node answering the commands registered with Handle
+// and Reply, other commands get the 404 of an unknown command.
+type fakeNode struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc
+	calls    map[string]int
+}
+
+// newFakeNode starts a fake node, the caller must close it.
+func newFakeNode(t *testing.T) *fakeNode {
+	n := &fakeNode{
+		t:        t,
+		handlers: make(map[string]http.HandlerFunc),
+		calls:    make(map[string]int),
+	}
+	n.Server = httptest.NewServer(http.HandlerFunc(n.serve))
+	return n
+}
+
+func (n *fakeNode) serve(w http.ResponseWriter, r *http.Request) {
+	command := strings.TrimPrefix(r.URL.Path, "/api/v0/")
+	n.mu.Lock()
+	n.calls[command]++
+	h, ok := n.handlers[command]
+	n.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	h(w, r)
+}
+
+// Handle registers the handler of command, e.g. "lfs/list_objects".
+func (n *fakeNode) Handle(command string, h http.HandlerFunc) {
+	n.mu.Lock()
+	n.handlers[command] = h
+	n.mu.Unlock()
+}
+
+// Reply registers a fixed reply of command.
+func (n *fakeNode) Reply(command, body string) {
+	n.Handle(command, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+}
+
+// Calls returns the number of requests received for command.
+func (n *fakeNode) Calls(command string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[command]
+}
+
+// Client returns a client of the node without credentials.
+func (n *fakeNode) Client() *Client {
+	c, err := New(n.Listener.Addr().String(), "", "", false)
+	if err != nil {
+		n.t.Fatal(err)
+	}
+	return c
+}
+
+// nodeError writes a command error the way the node does.
+func nodeError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]interface{}{"Message": message, "Code": cmdsErrNormal, "Type": "error"})
+}
+
+// readUpload returns the file of a command upload, failing the test
+// when the request is not a multipart form with a boundary, which the
+// node rejects.
+func readUpload(t *testing.T, r *http.Request) []byte {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		t.Errorf("expected a multipart upload, got Content-Type %q", r.Header.Get("Content-Type"))
+		return nil
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	part, err := mr.NextPart()
+	if err != nil {
+		t.Error(err)
+		return nil
+	}
+	data, err := ioutil.ReadAll(part)
+	if err != nil {
+		t.Error(err)
+	}
+	return data
+}
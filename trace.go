@@ -0,0 +1,179 @@
+package mefs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+)
+
+// traceBodyLimit - bodies larger than this are elided from the
+// command traces, e.g. object uploads and downloads.
+const traceBodyLimit = 4 * 1024
+
+// traceTransport dumps node command requests and responses to the
+// client trace output, it wraps the transport of the client while
+// tracing is enabled.
+type traceTransport struct {
+	c    *Client
+	base http.RoundTripper
+}
+
+// commandHTTPClient - returns the http client used for node commands.
+func (c *Client) commandHTTPClient() *http.Client {
+	if !c.isTraceEnabled {
+		return c.httpClient
+	}
+	hc := *c.httpClient
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hc.Transport = &traceTransport{c: c, base: base}
+	return &hc
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// When traceErrorsOnly is enabled successful responses are skipped.
+	if t.c.traceErrorsOnly && resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+
+	if err = t.c.dumpCommand(req, resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+// dumpCommand - dump a node command request and response headers,
+// the response body is dumped by traceBody once it is consumed.
+func (c Client) dumpCommand(req *http.Request, resp *http.Response) error {
+	// Starts http dump.
+	_, err := fmt.Fprintln(c.traceOutput, "---------START-HTTP---------")
+	if err != nil {
+		return err
+	}
+
+	// Filter out secrets from the query and Authorization header.
+	redacted := *req
+	u := *req.URL
+	u.RawQuery = redactQuery(u.RawQuery)
+	redacted.URL = &u
+	redacted.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		redacted.Header[k] = append([]string(nil), v...)
+	}
+	if origAuth := req.Header.Get("Authorization"); origAuth != "" {
+		redacted.Header.Set("Authorization", redactSignature(origAuth))
+	}
+
+	reqTrace, err := httputil.DumpRequestOut(&redacted, false)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(c.traceOutput, string(reqTrace))
+	if err != nil {
+		return err
+	}
+
+	// Only small replayable request bodies are displayed.
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody := fmt.Sprintf("[request body of %d bytes elided]\n", req.ContentLength)
+		if req.ContentLength < 0 {
+			reqBody = "[streamed request body elided]\n"
+		}
+		if req.GetBody != nil && req.ContentLength > 0 && req.ContentLength <= traceBodyLimit {
+			rc, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			b, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+			reqBody = string(redactBody(b)) + "\n"
+		}
+		_, err = fmt.Fprint(c.traceOutput, reqBody)
+		if err != nil {
+			return err
+		}
+	}
+
+	respTrace, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(c.traceOutput, strings.TrimSuffix(string(respTrace), "\r\n"))
+	if err != nil {
+		return err
+	}
+
+	resp.Body = &traceBody{ReadCloser: resp.Body, out: c.traceOutput}
+	return nil
+}
+
+// traceBody records the beginning of a response body and writes it,
+// followed by the end of the dump, when the body is fully read or
+// closed. Streamed command outputs are thus traced without being
+// buffered.
+type traceBody struct {
+	io.ReadCloser
+	out io.Writer
+
+	buf   bytes.Buffer
+	total int64
+	once  sync.Once
+}
+
+func (t *traceBody) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if n > 0 {
+		t.total += int64(n)
+		if room := traceBodyLimit - t.buf.Len(); room > 0 {
+			if room > n {
+				room = n
+			}
+			t.buf.Write(p[:room])
+		}
+	}
+	if err != nil {
+		t.flush()
+	}
+	return n, err
+}
+
+func (t *traceBody) Close() error {
+	t.flush()
+	return t.ReadCloser.Close()
+}
+
+// flush - writes the recorded body and ends the http dump.
+func (t *traceBody) flush() {
+	t.once.Do(func() {
+		body := redactBody(t.buf.Bytes())
+		elided := t.total - int64(t.buf.Len())
+		// the recording ends before a secret cut by the limit.
+		if loc := regCutSecret.FindIndex(body); loc != nil {
+			elided += int64(len(body) - loc[0])
+			body = body[:loc[0]]
+		}
+		if len(body) > 0 {
+			fmt.Fprintln(t.out, string(bytes.TrimRight(body, "\n")))
+		}
+		if elided > 0 {
+			fmt.Fprintf(t.out, "[%d more response bytes elided]\n", elided)
+		}
+		fmt.Fprintln(t.out, "---------END-HTTP---------")
+	})
+}
@@ -0,0 +1,76 @@
+package mefs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommandTrace(t *testing.T) {
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Handle("create", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Address":"0x01","Sk":"deadbeef"}`)
+	})
+	n.Reply("lfs/get_object", strings.Repeat("a", 3*traceBodyLimit))
+	cut := `{"Pad":"` + strings.Repeat("a", traceBodyLimit-20) + `","Sk":"deadbeefcafe"}`
+	n.Reply("lfs/show_storage", cut)
+
+	c := n.Client()
+	var buf bytes.Buffer
+	c.TraceOn(&buf)
+
+	var user UserPrivMessage
+	err := c.Request("create").Option("password", "123456").Exec(context.Background(), &user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Sk != "deadbeef" {
+		t.Fatalf("tracing altered the response, got %q", user.Sk)
+	}
+	out := buf.String()
+	for _, secret := range []string{"123456", "deadbeef"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("trace leaks %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, `"Sk":"**REDACTED**"`) || !strings.Contains(out, "---------END-HTTP---------") {
+		t.Errorf("unexpected trace:\n%s", out)
+	}
+
+	buf.Reset()
+	resp, err := c.Request("lfs/get_object", "b", "o").Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Close()
+	if !strings.Contains(buf.String(), fmt.Sprintf("[%d more response bytes elided]", 2*traceBodyLimit)) {
+		t.Errorf("expected elided body:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	resp, err = c.Request("lfs/show_storage").Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(resp.Output)
+	resp.Close()
+	// the capture stops at "Sk":"dead, 10 bytes before the limit.
+	if out := buf.String(); strings.Contains(out, "dead") || !strings.Contains(out, fmt.Sprintf("[%d more response bytes elided]", len(cut)-traceBodyLimit+10)) {
+		t.Errorf("trace leaks a secret cut by the limit:\n%s", out)
+	}
+
+	buf.Reset()
+	c.TraceErrorsOnlyOn(&buf)
+	if err = c.Request("create").Exec(context.Background(), &user); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("successful request traced with errors only:\n%s", buf.String())
+	}
+}
@@ -200,6 +200,41 @@ func redactSignature(origAuth string) string {
 	return regSign.ReplaceAllString(newAuth, "Signature=**REDACTED**")
 }
 
+// redactedKeys lists the command options and response fields, in
+// lower case, that carry MEFS secrets.
+var redactedKeys = map[string]struct{}{
+	"password":   {},
+	"secretekey": {},
+	"sk":         {},
+}
+
+// regSecret matches secret fields of a JSON body.
+var regSecret = regexp.MustCompile(`(?i)("(?:password|secretekey|sk)"\s*:\s*")[^"]*(")`)
+
+// regCutSecret matches a secret field cut at the end of a truncated
+// body, which regSecret misses.
+var regCutSecret = regexp.MustCompile(`(?i)"(?:password|secretekey|sk)"\s*:\s*"[^"]*$`)
+
+// redactQuery - returns the encoded query with the values of
+// redactedKeys replaced.
+func redactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "**REDACTED**"
+	}
+	for k := range values {
+		if _, ok := redactedKeys[strings.ToLower(k)]; ok {
+			values.Set(k, "**REDACTED**")
+		}
+	}
+	return values.Encode()
+}
+
+// redactBody - replaces the values of secret fields in a JSON body.
+func redactBody(body []byte) []byte {
+	return regSecret.ReplaceAll(body, []byte("${1}**REDACTED**${2}"))
+}
+
 // Get default location returns the location based on the input
 // URL `u`, if region override is provided then all location
 // defaults to regionOverride.
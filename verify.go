@@ -0,0 +1,83 @@
+package mefs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	cid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// ErrUnverifiableKey is returned by VerifyBlock for keys which are not
+// a CID or a multihash, their content cannot be checked.
+var ErrUnverifiableKey = errors.New("block key is not a CID, its content cannot be verified")
+
+// IntegrityError is returned when the content of a block does not
+// match the multihash of its key.
+type IntegrityError struct {
+	Key      string
+	Expected mh.Multihash
+	Actual   mh.Multihash
+}
+
+func (e *IntegrityError) Error() string {
+	return fmt.Sprintf("block %s: content hashes to %s, expected %s", e.Key, e.Actual.B58String(), e.Expected.B58String())
+}
+
+// VerifyBlock - hashes data with the multihash function encoded in
+// key and compares the digests.
+func VerifyBlock(key string, data []byte) error {
+	c, err := cid.Decode(key)
+	if err != nil {
+		return ErrUnverifiableKey
+	}
+	expected := c.Hash()
+	dec, err := mh.Decode(expected)
+	if err != nil {
+		return ErrUnverifiableKey
+	}
+	actual, err := mh.Sum(data, dec.Code, dec.Length)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(actual, expected) {
+		return &IntegrityError{Key: key, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// SetBlockVerification - turns the client-side verification of blocks
+// on or off. When on, blocks fetched by BlockGet, BlockGetReader and
+// GetBlockFrom and keys returned by BlockPut are checked with
+// VerifyBlock. Fetched blocks whose key is not a CID, like the
+// internal block ids of LFS objects, cannot be checked and are
+// passed through; BlockPut must return a CID.
+func (c *Client) SetBlockVerification(enabled bool) {
+	c.verifyBlocks = enabled
+}
+
+// verifyingReader buffers a block and verifies it at EOF, the EOF is
+// replaced by the verification error on mismatch.
+type verifyingReader struct {
+	io.ReadCloser
+	key string
+	buf bytes.Buffer
+	err error
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.buf.Write(p[:n])
+	if err == io.EOF {
+		if verr := VerifyBlock(r.key, r.buf.Bytes()); verr != nil && verr != ErrUnverifiableKey {
+			r.err = verr
+			return n, verr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,71 @@
+package mefs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+func TestVerifyBlock(t *testing.T) {
+	sum, err := mh.Sum([]byte("hello"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := sum.B58String()
+
+	if err = VerifyBlock(key, []byte("hello")); err != nil {
+		t.Errorf("expected valid block, got %v", err)
+	}
+	if _, ok := VerifyBlock(key, []byte("hellO")).(*IntegrityError); !ok {
+		t.Error("expected IntegrityError")
+	}
+	if err = VerifyBlock("b_0_1", []byte("hello")); err != ErrUnverifiableKey {
+		t.Errorf("expected ErrUnverifiableKey, got %v", err)
+	}
+
+	n := newFakeNode(t)
+	defer n.Close()
+	n.Reply("block/get", "tampered")
+	n.Reply("block/getfrom", `"tampered"`)
+	n.Handle("block/put", func(w http.ResponseWriter, r *http.Request) {
+		readUpload(t, r)
+		fmt.Fprintf(w, `{"Key":"%s"}`, key)
+	})
+
+	c := n.Client()
+	if _, err = c.BlockGet(key); err != nil {
+		t.Errorf("verification is off by default, got %v", err)
+	}
+
+	c.SetBlockVerification(true)
+	if _, err = c.BlockGet(key); err == nil {
+		t.Error("expected IntegrityError from BlockGet")
+	}
+	if _, err = c.GetBlockFrom(key, testPeerID); err == nil {
+		t.Error("expected IntegrityError from GetBlockFrom")
+	}
+	if _, err = c.GetBlockFrom("b_0_1", testPeerID); err != nil {
+		t.Errorf("non CID keys are not verified, got %v", err)
+	}
+	if calls := n.Calls("block/get"); calls != 2 {
+		t.Errorf("expected GetBlockFrom to verify the provider reply, got %d local reads", calls-2)
+	}
+	rc, err := c.BlockGetReader(context.Background(), "b_0_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ioutil.ReadAll(rc); err != nil {
+		t.Errorf("non CID keys are not verified, got %v", err)
+	}
+	rc.Close()
+	if _, err = c.BlockPut([]byte("hello"), "v0", "sha2-256", -1); err != nil {
+		t.Errorf("expected matching key, got %v", err)
+	}
+	if _, err = c.BlockPut([]byte("other"), "v0", "sha2-256", -1); err == nil {
+		t.Error("expected IntegrityError from BlockPut")
+	}
+}